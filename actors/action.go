@@ -2,7 +2,6 @@ package actors
 
 import (
 	"fmt"
-	"math"
 
 	"enewey.com/golang-game/config"
 	"enewey.com/golang-game/events"
@@ -19,6 +18,14 @@ const (
 	JumpActionType
 	DashActionType
 	ChangePosActionType
+	DamageActionType
+	KnockbackActionType
+	HopActionType
+	OrbitActionType
+	RunActionType
+	LiftActionType
+	ThrowActionType
+	SwimActionType
 )
 
 // InterpretEvent - translate an event into an action
@@ -27,18 +34,58 @@ func InterpretEvent(ev *events.Event) Action {
 	p := ev.Payload()
 	switch ev.Code() {
 	case MoveToActionType:
-		// TODO: Do we need this? I mean probably, but...
+		fmt.Printf("moveto action interpreted %v\n", ev.Payload())
+		return NewMoveToAction(p[0].(Actor), p[1].(float64), p[2].(float64), p[3].(float64), p[4].(int),
+			utils.EasingByName(p[5].(string)))
 	case MoveByActionType:
 		fmt.Printf("moveby action interpreted %v\n", ev.Payload())
+		if len(p) > 5 {
+			return NewEasedMoveByAction(p[0].(Actor), p[1].(float64), p[2].(float64), p[3].(float64), p[4].(int),
+				utils.EasingByName(p[5].(string)))
+		}
 		return NewMoveByAction(p[0].(Actor), p[1].(float64), p[2].(float64), p[3].(float64), p[4].(int))
 	case JumpActionType:
 		fmt.Printf("jump action interpreted %v\n", ev.Payload())
+		if len(p) > 2 && p[2].(bool) {
+			return NewCoyoteJumpAction(p[0].(Actor), p[1].(float64))
+		}
 		return NewJumpAction(p[0].(Actor), p[1].(float64))
 	case DashActionType:
 		fmt.Printf("dash action interpreted %v\n", ev.Payload())
 		return NewDashAction(p[0].(Actor), p[1].(float64), p[2].(float64), p[3].(float64))
 	case ChangePosActionType:
-
+		fmt.Printf("changepos action interpreted %v\n", ev.Payload())
+		return NewChangePosAction(p[0].(Actor), p[1].(int), p[2].(int), p[3].(int))
+	case DamageActionType:
+		fmt.Printf("damage action interpreted %v\n", ev.Payload())
+		var source Actor
+		if p[1] != nil {
+			source = p[1].(Actor)
+		}
+		return NewDamageAction(p[0].(Actor), source, p[2].(int), p[3].(float64))
+	case KnockbackActionType:
+		fmt.Printf("knockback action interpreted %v\n", ev.Payload())
+		return NewKnockbackAction(p[0].(Actor), p[1].(float64), p[2].(float64), p[3].(float64), p[4].(int))
+	case HopActionType:
+		fmt.Printf("hop action interpreted %v\n", ev.Payload())
+		return NewHopAction(p[0].(Actor), p[1].(float64), p[2].(float64), p[3].(float64), p[4].(float64), p[5].(int),
+			utils.EasingByName(p[6].(string)))
+	case OrbitActionType:
+		fmt.Printf("orbit action interpreted %v\n", ev.Payload())
+		return NewOrbitAction(p[0].(Actor), p[1].(float64), p[2].(float64), p[3].(float64), p[4].(int),
+			utils.EasingByName(p[5].(string)))
+	case RunActionType:
+		fmt.Printf("run action interpreted %v\n", ev.Payload())
+		return p[0].(Action)
+	case LiftActionType:
+		fmt.Printf("lift action interpreted %v\n", ev.Payload())
+		return NewLiftAction(p[0].(Actor), p[1].(CanMove))
+	case ThrowActionType:
+		fmt.Printf("throw action interpreted %v\n", ev.Payload())
+		return NewThrowAction(p[0].(Actor))
+	case SwimActionType:
+		fmt.Printf("swim action interpreted %v\n", ev.Payload())
+		return NewSwimAction(p[0].(Actor), p[1].(float64))
 	default:
 		fmt.Printf("unknown actor event code %d\n", ev.Code())
 	}
@@ -54,18 +101,31 @@ type Action interface {
 	// A completed Action is to be discarded.
 }
 
-// Actions - an array of actions
+// Actions - a queue of actions. Completed actions leave empty slots behind, which get reused.
 type Actions []Action
 
-// Add - Add a new action. Keeps the slice slim.
-func (acts Actions) Add(a Action) {
-	for i, v := range acts {
+// Add - Add a new action. Keeps the slice slim by filling empty slots first,
+// and grows the slice when it is full.
+func (acts *Actions) Add(a Action) {
+	if a == nil {
+		return
+	}
+	for i, v := range *acts {
 		if v == nil {
-			acts[i] = a
+			(*acts)[i] = a
 			return
 		}
 	}
-	acts = append(acts, a)
+	*acts = append(*acts, a)
+}
+
+// Remove - Remove an action before it completes, leaving its slot empty.
+func (acts *Actions) Remove(a Action) {
+	for i, v := range *acts {
+		if v == a {
+			(*acts)[i] = nil
+		}
+	}
 }
 
 // BaseAction - common data type for all actions.
@@ -83,96 +143,43 @@ func (b *BaseAction) Elapsed() types.Frame { return b.elapsed }
 
 // --- Action Definitions
 
-// MoveToAction - moves the Actor to a specific position at a given speed.
-type MoveToAction struct {
-	BaseAction
-	sx, sy, sz float64 // starting x/y/z
-	tx, ty, tz float64 // target x/y/z
-	speed      float64 // pixels per 0.0167 seconds
-}
-
-// Process - processes a MoveToAction
-func (a *MoveToAction) Process(df int) bool {
-	x, y, z := a.target.Pos()
-	target := a.target.(CanMove)
-	a.elapsed += df
-	if (float64(x) == a.tx && float64(y) == a.ty && float64(z) == a.tz) || a.elapsed > a.duration {
-		target.SetVel(0, 0, 0)
-		return true
-	}
-	vx := calcMoveToVel(a.sx, a.tx, float64(x), a.speed, a.elapsed)
-	vy := calcMoveToVel(a.sy, a.ty, float64(y), a.speed, a.elapsed)
-	vz := calcMoveToVel(a.sz, a.tz, float64(z), a.speed, a.elapsed)
-	target.SetVel(vx, vy, vz)
-	return false
-}
-
-// MoveByAction woo
-type MoveByAction struct {
-	BaseAction
-	dx, dy, dz float64 // delta x/y/z
-	vx, vy, vz float64
-}
-
-// NewMoveByAction woo
-func NewMoveByAction(target Actor, dx, dy, dz float64, duration types.Frame) *MoveByAction {
-	return &MoveByAction{
-		BaseAction{
-			target,
-			duration,
-			0,
-		},
-		dx, dy, dz,
-		dx / float64(duration),
-		dy / float64(duration),
-		dz / float64(duration),
-	}
-}
-
-// Process w
-func (a *MoveByAction) Process(df types.Frame) bool {
-	// _, _, vz := target.Vel()
-	a.elapsed += df
-	target := a.target.(CanMove)
-	if a.elapsed > a.duration {
-		target.SetVel(0, 0, 0)
-		return true
-	}
-	target.SetVel(a.vx, a.vy, a.vz)
-	return false
-}
-
-func calcMoveToVel(start, end, current float64, speed float64, elapsed types.Frame) float64 {
-	projectedDist := speed * float64(elapsed) // hmm.. rounding?
-	actualDist := current - start
-	destinationDist := end - start
-	if actualDist < 0 {
-		projectedDist *= -1
-	}
-	if math.Abs(projectedDist) > math.Abs(float64(destinationDist)) {
-		return float64(destinationDist - actualDist)
-	}
-	return projectedDist - float64(actualDist)
-}
-
 // JumpAction w
 type JumpAction struct {
 	BaseAction
-	v float64
+	v      float64
+	coyote bool // jump even if the target just walked off a ledge
 }
 
 // NewJumpAction w
 func NewJumpAction(target Actor, v float64) *JumpAction {
-	return &JumpAction{BaseAction{target, 0, 0}, v}
+	return &JumpAction{BaseAction{target, 0, 0}, v, false}
+}
+
+// NewCoyoteJumpAction creates a jump that goes ahead even when the target isn't on the ground,
+// for jumps pressed just after walking off a ledge.
+func NewCoyoteJumpAction(target Actor, v float64) *JumpAction {
+	return &JumpAction{BaseAction{target, 0, 0}, v, true}
 }
 
 // Process w
 func (a *JumpAction) Process(df types.Frame) bool {
 	target := a.target.(CanMove)
-	if target.OnGround() {
-		vx, vy, _ := target.Vel()
-		target.SetVel(vx, vy, a.v)
+	m := target.Mass()
+	_, _, vz := target.Vel()
+	if climbing(target) {
+		// letting go; push off away from whatever was being climbed. this takes over from
+		// the climbing controls, which steer the climber.
+		px, py := DirToVec(target.Direction())
+		target.Steer(-float64(px), -float64(py), a.v)
+		if stater, ok := target.(Stateful); ok {
+			stater.SetState(StateJump)
+		}
+	} else if target.OnGround() || a.coyote {
+		target.AddImpulse(0, 0, (a.v-vz)*m)
 		target.SetOnGround(false)
+		if stater, ok := target.(Stateful); ok {
+			stater.SetState(StateJump)
+		}
 	}
 	return true
 }
@@ -193,27 +200,31 @@ func NewDashAction(target Actor, vx, vy, vz float64) *DashAction {
 // Process w
 func (a *DashAction) Process(df types.Frame) bool {
 	target := a.target.(CanMove)
-	if dasher, ok := target.(CanDash); ok {
-		dasher.SetDashed(true)
-	} else {
+	dasher, ok := target.(Stateful)
+	if !ok {
+		return true
+	}
+	// the dash is cut short if the actor is knocked out of the dash state
+	if a.elapsed == 0 {
+		if !dasher.SetState(StateDash) && dasher.State() != StateDash {
+			return true
+		}
+	} else if dasher.State() != StateDash {
 		return true
 	}
 
-	// target.SetControlled(false)
-
-	vx, vy, vz := a.axes.RejectVec(target.Vel())
 	if a.axes.IsZ() {
 		a.vz += config.Get().Gravity()
 	}
-
-	target.SetVel(
-		a.vx+vx,
-		a.vy+vy,
-		a.vz+vz,
-	)
+	// only the axes of the dash are steered; the others are left to whatever acts on them
+	target.SteerAxes(a.axes, a.vx, a.vy, a.vz)
 
 	a.elapsed += df
-	return a.elapsed >= a.duration && (target.OnGround() || a.axes.IsZ())
+	if a.elapsed >= a.duration && (target.OnGround() || a.axes.IsZ()) {
+		dasher.SetState(groundedState(target))
+		return true
+	}
+	return false
 }
 
 // ChangePosAction will change the position of the target actor immediately.
@@ -234,3 +245,91 @@ func (a *ChangePosAction) Process(df types.Frame) bool {
 	target.Collider().SetPos(a.x, a.y, a.z)
 	return true
 }
+
+// DamageAction removes health from the target, knocks it back away from the
+// source, and announces its death if its health runs out.
+type DamageAction struct {
+	BaseAction
+	source    Actor
+	amount    int
+	knockback float64
+}
+
+// knockbackFrames is how long an actor is stunned when knocked back
+const knockbackFrames types.Frame = 12
+
+// NewDamageAction creates a new damage action. The source may be nil.
+func NewDamageAction(target, source Actor, amount int, knockback float64) *DamageAction {
+	return &DamageAction{BaseAction{target, 0, 0}, source, amount, knockback}
+}
+
+// Process - applies the damage immediately
+func (a *DamageAction) Process(df types.Frame) bool {
+	d, ok := a.target.(Damageable)
+	if !ok || d.Health() == nil || d.Health().Dead() {
+		return true
+	}
+	if !d.Health().Damage(a.amount) {
+		return true
+	}
+
+	if _, ok := a.target.(CanMove); ok && a.source != nil && a.knockback > 0 {
+		x1, y1, _ := a.target.Collider().Center()
+		x2, y2, _ := a.source.Collider().Center()
+		kx, ky := float64(x1-x2), float64(y1-y2)
+		if kx == 0 && ky == 0 {
+			ky = 1
+		}
+		kx, ky = utils.Normalize2(kx, ky)
+		events.Enqueue(NewKnockbackEvent(a.target,
+			kx*a.knockback, ky*a.knockback, a.knockback/2, knockbackFrames))
+	}
+
+	if d.Health().Dead() {
+		events.Enqueue(NewDeathEvent(a.target))
+	}
+	return true
+}
+
+// KnockbackAction pushes the target with a decaying velocity, stunning it for the duration.
+type KnockbackAction struct {
+	BaseAction
+	vx, vy, vz float64
+}
+
+// NewKnockbackAction creates a new knockback action.
+func NewKnockbackAction(target Actor, vx, vy, vz float64, duration types.Frame) *KnockbackAction {
+	return &KnockbackAction{BaseAction{target, duration, 0}, vx, vy, vz}
+}
+
+// Process w
+func (a *KnockbackAction) Process(df types.Frame) bool {
+	target := a.target.(CanMove)
+	stater, stateful := a.target.(Stateful)
+	vx, vy, _ := target.Vel()
+	m := target.Mass()
+	if a.elapsed == 0 {
+		if stateful {
+			stater.SetState(StateStunned)
+		}
+		// heavier actors are knocked back less
+		target.AddImpulse(a.vx, a.vy, a.vz)
+		target.SetOnGround(false)
+		a.elapsed += df
+		return false
+	}
+
+	a.elapsed += df
+	if a.elapsed > a.duration {
+		target.AddImpulse(-vx*m, -vy*m, 0)
+		if stateful && stater.State() == StateStunned {
+			stater.SetState(groundedState(target))
+		}
+		return true
+	}
+
+	// brake evenly over the rest of the knockback
+	remaining := float64(a.duration - a.elapsed + 1)
+	target.AddForce(-vx*m/remaining, -vy*m/remaining, 0)
+	return false
+}
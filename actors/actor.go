@@ -66,8 +66,16 @@ type CanMove interface {
 	SetVelZ(z float64)
 	Collider() colliders.Collider
 	Weight() int
+	Medium() *colliders.Medium
+	Ground() colliders.Collider
+	Mass() float64
+	AddForce(x, y, z float64)
+	AddImpulse(x, y, z float64)
+	Steer(x, y, z float64)
+	SteerAxes(axes *types.AxisMap, x, y, z float64)
 
 	Direction() types.Direction
+	SetDirection(types.Direction)
 	FacingVertical() bool
 	FacingHorizontal() bool
 	Orthogonal() bool
@@ -86,14 +94,6 @@ type Controllable interface {
 
 var _ Controllable = &CharActor{}
 
-// CanDash w
-type CanDash interface {
-	Dashed() bool
-	SetDashed(bool)
-}
-
-var _ CanDash = &CharActor{}
-
 // Drawable is an interface for entities which can be drawn on the screen
 type Drawable interface {
 	DrawOffset() (int, int)
@@ -128,6 +128,9 @@ type baseActor struct {
 	category string // denotes the "type" of actor
 
 	collider colliders.Collider
+
+	health   *Health
+	hitboxes []*Hitbox
 }
 
 // ID - unique id for actor
@@ -162,7 +165,7 @@ type InvisibleActor struct {
 
 // NewInvisibleActor returns a new invisible actor with the provided collider.
 func NewInvisibleActor(category string, collider colliders.Collider) *InvisibleActor {
-	return &InvisibleActor{baseActor{-1, category, collider}}
+	return &InvisibleActor{baseActor{-1, category, collider, nil, nil}}
 }
 
 // CanCollide tells whether this actor can resolve collisions.
@@ -184,7 +187,7 @@ func NewSpriteActor(
 	ox, oy int,
 ) *SpriteActor {
 	return &SpriteActor{
-		baseActor{-1, category, collider},
+		baseActor{-1, category, collider, nil, nil},
 		sprite,
 		ox, oy,
 	}
@@ -202,6 +205,9 @@ func (a *SpriteActor) DrawPos() (int, int) {
 }
 
 func (a *SpriteActor) draw(img *ebiten.Image, offsetX, offsetY int) *ebiten.Image {
+	if a.flashing() {
+		return img
+	}
 	x, y, z := a.Pos()
 	return a.spritemap.Sprite(0).Draw(x+a.ox+offsetX, y-z+a.oy+offsetY, img)
 }
@@ -272,6 +278,13 @@ type MovingActor struct {
 	direction        types.Direction
 	weight           int
 	onGround         bool
+	volume           colliders.Collider // the volume the actor is inside of
+	ground           colliders.Collider // the collider the actor is standing on
+	mass, drag       float64
+	fx, fy, fz       float64        // forces accumulated this frame
+	ix, iy, iz       float64        // impulses accumulated this frame
+	steering         *types.AxisMap // axes steered this frame, or nil
+	sx, sy, sz       float64        // velocity steered at this frame
 }
 
 // NewMovingActor creates a new MovingActor, which is like a static actor that can move.
@@ -290,6 +303,13 @@ func NewMovingActor(
 		types.Down,
 		weight,
 		true,
+		nil,
+		nil,
+		1, 0,
+		0, 0, 0,
+		0, 0, 0,
+		nil,
+		0, 0, 0,
 	}
 }
 
@@ -333,6 +353,9 @@ func (a *MovingActor) SetSubPos(cx, cy, cz float64) {
 // Direction - gets the last calculated direction for this actor
 func (a *MovingActor) Direction() types.Direction { return a.direction }
 
+// SetDirection - turns the actor to face a direction
+func (a *MovingActor) SetDirection(d types.Direction) { a.direction = d }
+
 // FacingVertical returns true if the actor's direction is Up or Down
 func (a *MovingActor) FacingVertical() bool {
 	return (a.direction == types.Up || a.direction == types.Down)
@@ -366,6 +389,9 @@ func (a *MovingActor) OnGround() bool { return a.onGround }
 // SetOnGround woo
 func (a *MovingActor) SetOnGround(b bool) { a.onGround = b }
 
+// Ground - the collider the actor is standing on, or nil if it is in the air
+func (a *MovingActor) Ground() colliders.Collider { return a.ground }
+
 // Weight is the priority of this actor in terms of blocking; heavier actors will push around lighter actors.
 func (a *MovingActor) Weight() int { return a.weight }
 
@@ -373,8 +399,11 @@ func (a *MovingActor) Weight() int { return a.weight }
 type CharActor struct {
 	MovingActor
 
-	controlled bool
-	dashed     bool
+	sm       *StateMachine
+	carrying CanMove
+
+	touchingClimbable bool
+	stateSprites      map[CharState]sprites.Spritemap
 }
 
 // NewCharActor create a new char actor
@@ -387,25 +416,32 @@ func NewCharActor(
 ) Actor {
 	return &CharActor{
 		*NewMovingActor(category, sprite, collider, ox, oy, weight, true).(*MovingActor),
-		false, false,
+		NewStateMachine(),
+		nil,
+		false,
+		nil,
 	}
 }
 
-// Dashed - get the "dashed" state -- set by the dash action.
-func (a *CharActor) Dashed() bool { return a.dashed }
-
-// SetDashed woow
-func (a *CharActor) SetDashed(b bool) { a.dashed = b }
-
 // Controlled - this actor is being controlled by actions and cannot respond to input
-func (a *CharActor) Controlled() bool { return a.controlled }
+func (a *CharActor) Controlled() bool { return a.State() == StateControlled }
 
 // SetControlled - this actor is being controlled by actions and cannot respond to input
-func (a *CharActor) SetControlled(b bool) { a.controlled = b }
+func (a *CharActor) SetControlled(b bool) {
+	if b {
+		a.SetState(StateControlled)
+	} else if a.Controlled() {
+		if a.OnGround() {
+			a.SetState(StateIdle)
+		} else {
+			a.SetState(StateFall)
+		}
+	}
+}
 
 // Sprite woo
 func (a *CharActor) Sprite() *sprites.Sprite {
-	return a.spritemap.Sprite(int(a.direction))
+	return a.currentSpritemap().Sprite(int(a.direction))
 }
 
 // DrawPos - returns the position this actor should be drawn in world space
@@ -415,8 +451,11 @@ func (a *CharActor) DrawPos() (int, int) {
 }
 
 func (a *CharActor) draw(img *ebiten.Image, offsetX, offsetY int) *ebiten.Image {
+	if a.flashing() {
+		return img
+	}
 	x, y := a.DrawPos()
-	return a.spritemap.Sprite(int(a.direction)).Draw(x+offsetX, y+offsetY, img)
+	return a.currentSpritemap().Sprite(int(a.direction)).Draw(x+offsetX, y+offsetY, img)
 }
 
 // DrawOffset s
@@ -0,0 +1,357 @@
+package actors
+
+import (
+	"math"
+	"math/rand"
+
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/input"
+	"enewey.com/golang-game/nav"
+	"enewey.com/golang-game/types"
+	"enewey.com/golang-game/utils"
+)
+
+// This file contains controllers for non-player actors.
+// Like the player controller, they steer their actors with impulses each frame,
+// and they leave their actors alone when they are controlled or stunned.
+
+// locked tells whether the actor is in a state where controllers should not steer it
+func locked(a Actor) bool {
+	if stater, ok := a.(Stateful); ok {
+		return stater.State().Locked()
+	}
+	return false
+}
+
+// steer sets the actor's velocity towards a point on the XY plane at the given speed.
+// Returns true if the actor has arrived at the point.
+func steer(a CanMove, tx, ty, speed float64) bool {
+	x, y, _ := a.Collider().Pos()
+	dx, dy := tx-float64(x), ty-float64(y)
+	dist := utils.Magnitude2(dx, dy)
+	if dist <= speed || dist == 0 {
+		stop(a)
+		return true
+	}
+	drive(a, dx/dist*speed, dy/dist*speed)
+	return false
+}
+
+// stop brings the actor to a halt on the XY plane
+func stop(a CanMove) {
+	drive(a, 0, 0)
+}
+
+// drive changes the XY velocity of the actor to the given velocity, facing the way it goes
+func drive(a CanMove, vx, vy float64) {
+	x, y, _ := a.Vel()
+	m := a.Mass()
+	a.AddImpulse((vx-x)*m, (vy-y)*m, 0)
+	a.SetDirection(VecToDir(vx, vy, a.Direction()))
+}
+
+// distance between the centers of two actors on the XY plane
+func distance(a, b Actor) float64 {
+	ax, ay, _ := a.Collider().Center()
+	bx, by, _ := b.Collider().Center()
+	return utils.Magnitude2(float64(bx-ax), float64(by-ay))
+}
+
+// Region is a rectangular area on the XY plane
+type Region struct {
+	X, Y, W, H int
+}
+
+// Contains tells whether the point lies within the region
+func (r Region) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.W && y >= r.Y && y < r.Y+r.H
+}
+
+// WanderController moves an actor in random directions, staying within a region.
+type WanderController struct {
+	controller
+	region      Region
+	speed       float64
+	moveFrames  types.Frame
+	pauseFrames types.Frame
+	ticks       types.Frame
+	tx, ty      float64
+	moving      bool
+}
+
+// NewWanderController creates a controller that alternates between pausing and
+// wandering towards a random point in the region.
+func NewWanderController(region Region, speed float64, moveFrames, pauseFrames types.Frame) *WanderController {
+	return &WanderController{controller{-1}, region, speed, moveFrames, pauseFrames, 0, 0, 0, false}
+}
+
+// Tap w
+func (c *WanderController) Tap(target Actor, state input.Input, df types.Frame) bool {
+	mover, ok := target.(CanMove)
+	if !ok || locked(target) {
+		return false
+	}
+	c.ticks += df
+
+	if !c.moving {
+		stop(mover)
+		if c.ticks >= c.pauseFrames {
+			c.ticks, c.moving = 0, true
+			c.tx = float64(c.region.X + rand.Intn(utils.Max(c.region.W, 1)))
+			c.ty = float64(c.region.Y + rand.Intn(utils.Max(c.region.H, 1)))
+		}
+		return true
+	}
+
+	if steer(mover, c.tx, c.ty, c.speed) || c.ticks >= c.moveFrames {
+		stop(mover)
+		c.ticks, c.moving = 0, false
+	}
+	return true
+}
+
+// PatrolController moves an actor between waypoints, waiting at each one.
+type PatrolController struct {
+	controller
+	waypoints []types.Point
+	speed     float64
+	wait      types.Frame
+	loop      bool // loop back to the first waypoint, rather than reversing
+	current   int
+	step      int
+	ticks     types.Frame
+}
+
+// NewPatrolController creates a controller that walks the waypoints in order.
+// When looping, the actor walks from the last waypoint back to the first;
+// otherwise, it walks the waypoints in reverse.
+func NewPatrolController(waypoints []types.Point, speed float64, wait types.Frame, loop bool) *PatrolController {
+	return &PatrolController{controller{-1}, waypoints, speed, wait, loop, 0, 1, 0}
+}
+
+// Tap w
+func (c *PatrolController) Tap(target Actor, state input.Input, df types.Frame) bool {
+	mover, ok := target.(CanMove)
+	if !ok || locked(target) || len(c.waypoints) == 0 {
+		return false
+	}
+
+	if c.ticks > 0 {
+		stop(mover)
+		c.ticks -= df
+		return true
+	}
+
+	wp := c.waypoints[c.current]
+	if !steer(mover, float64(wp.X), float64(wp.Y), c.speed) {
+		return true
+	}
+
+	c.ticks = c.wait
+	if len(c.waypoints) == 1 {
+		return true
+	}
+	next := c.current + c.step
+	if next < 0 || next >= len(c.waypoints) {
+		if c.loop {
+			next = 0
+		} else {
+			c.step *= -1
+			next = c.current + c.step
+		}
+	}
+	c.current = next
+	return true
+}
+
+// Target is the actor a controller acts towards. It is looked up every time the controller is
+// tapped, so a controller targeting the player keeps up with whoever the player is.
+type Target func() Actor
+
+// TargetActor - a target that is always the same actor (which may be nil, for no target)
+func TargetActor(a Actor) Target {
+	return func() Actor { return a }
+}
+
+// TargetPlayer - a target that is whoever the manager's player is at the time
+func (m *Manager) TargetPlayer() Target {
+	return func() Actor { return m.GetPlayer() }
+}
+
+// FollowController moves an actor towards a target actor.
+// The actor stops once it is near enough, and ignores targets that are beyond its sight.
+type FollowController struct {
+	controller
+	target Target
+	speed  float64
+	near   float64
+	sight  float64 // zero means the target is always in sight
+}
+
+// NewFollowController creates a controller that follows the target, keeping some distance.
+func NewFollowController(target Target, speed, near float64) *FollowController {
+	return &FollowController{controller{-1}, target, speed, near, 0}
+}
+
+// NewChaseController creates a controller that runs down the target once it comes within sight.
+func NewChaseController(target Target, speed, sight float64) *FollowController {
+	return &FollowController{controller{-1}, target, speed, 0, sight}
+}
+
+// Tap w
+func (c *FollowController) Tap(target Actor, state input.Input, df types.Frame) bool {
+	mover, ok := target.(CanMove)
+	goal := c.target()
+	if !ok || locked(target) || goal == nil {
+		return false
+	}
+	dist := distance(target, goal)
+	if dist <= c.near || (c.sight > 0 && dist > c.sight) {
+		stop(mover)
+		return true
+	}
+	tx, ty, _ := goal.Collider().Pos()
+	steer(mover, float64(tx), float64(ty), c.speed)
+	return true
+}
+
+// FleeController moves an actor directly away from a threat when it comes too close.
+type FleeController struct {
+	controller
+	threat Target
+	speed  float64
+	radius float64
+}
+
+// NewFleeController creates a controller that runs from the threat while within the radius.
+func NewFleeController(threat Target, speed, radius float64) *FleeController {
+	return &FleeController{controller{-1}, threat, speed, radius}
+}
+
+// Tap w
+func (c *FleeController) Tap(target Actor, state input.Input, df types.Frame) bool {
+	mover, ok := target.(CanMove)
+	threat := c.threat()
+	if !ok || locked(target) || threat == nil {
+		return false
+	}
+	if distance(target, threat) > c.radius {
+		stop(mover)
+		return true
+	}
+	ax, ay, _ := target.Collider().Center()
+	bx, by, _ := threat.Collider().Center()
+	dx, dy := float64(ax-bx), float64(ay-by)
+	if dx == 0 && dy == 0 {
+		dy = 1
+	}
+	dx, dy = utils.Normalize2(dx, dy)
+	drive(mover, dx*c.speed, dy*c.speed)
+	return true
+}
+
+// FaceController turns an actor to face a target when the target comes near.
+type FaceController struct {
+	controller
+	target Target
+	radius float64
+}
+
+// NewFaceController creates a controller that faces the target while it is within the radius.
+func NewFaceController(target Target, radius float64) *FaceController {
+	return &FaceController{controller{-1}, target, radius}
+}
+
+// Tap w
+func (c *FaceController) Tap(target Actor, state input.Input, df types.Frame) bool {
+	mover, ok := target.(CanMove)
+	goal := c.target()
+	if !ok || locked(target) || goal == nil {
+		return false
+	}
+	if distance(target, goal) > c.radius {
+		return true
+	}
+	ax, ay, _ := target.Collider().Center()
+	bx, by, _ := goal.Collider().Center()
+	mover.SetDirection(FaceDir(float64(bx-ax), float64(by-ay)))
+	return true
+}
+
+// FaceDir converts a vector into the nearest orthogonal Direction
+func FaceDir(dx, dy float64) types.Direction {
+	if math.Abs(dx) > math.Abs(dy) {
+		return VecToDir(utils.Normalize(dx), 0, types.Down)
+	}
+	return VecToDir(0, utils.Normalize(dy), types.Down)
+}
+
+// SeekController walks an actor to a target actor along a path through the navigation grid.
+// Each step of the path is issued as a move event, with a jump event where the path calls for one.
+type SeekController struct {
+	controller
+	grid   *nav.Grid
+	target Target
+	speed  float64
+	repath types.Frame
+	path   []nav.Step
+	ticks  types.Frame // frames since the last path was found
+	wait   types.Frame // frames until the current step is finished
+}
+
+// NewSeekController creates a controller that follows a path to the target,
+// finding a new path every so often in case the target moves.
+func NewSeekController(grid *nav.Grid, target Target, speed float64, repath types.Frame) *SeekController {
+	return &SeekController{controller{-1}, grid, target, speed, repath, nil, 0, 0}
+}
+
+// Tap w
+func (c *SeekController) Tap(target Actor, state input.Input, df types.Frame) bool {
+	goal := c.target()
+	if _, ok := target.(CanMove); !ok || locked(target) || goal == nil || c.grid == nil {
+		return false
+	}
+	c.ticks += df
+	if c.wait > 0 {
+		c.wait -= df
+		return true
+	}
+
+	if len(c.path) == 0 || c.ticks >= c.repath {
+		x, y, _ := target.Collider().Center()
+		tx, ty, _ := goal.Collider().Center()
+		c.path = c.grid.FindPath(x, y, tx, ty)
+		c.ticks = 0
+		// the last step is the target's own cell; stop short of walking into it
+		if len(c.path) > 0 {
+			c.path = c.path[:len(c.path)-1]
+		}
+	}
+	if len(c.path) == 0 {
+		return true
+	}
+
+	evs, duration := NewStepEvents(target, c.path[0], c.speed)
+	c.path = c.path[1:]
+	events.EnqueueAll(evs)
+	c.wait = duration
+	return true
+}
+
+// NewStepEvents creates the events for an actor to walk (or jump) to a step of a path,
+// at the given speed. Returns the events and the number of frames the move will take.
+func NewStepEvents(subject Actor, step nav.Step, speed float64) ([]*events.Event, types.Frame) {
+	x, y, _ := subject.Collider().Center()
+	dx, dy := float64(step.X-x), float64(step.Y-y)
+	duration := types.Frame(math.Ceil(utils.Magnitude2(dx, dy) / speed))
+	if duration < 1 {
+		duration = 1
+	}
+
+	evs := []*events.Event{}
+	if step.Jump {
+		evs = append(evs, NewJumpEvent(subject, 3.5))
+	}
+	evs = append(evs, NewMoveByEvent(subject, dx, dy, 0, duration))
+	return evs, duration
+}
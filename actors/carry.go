@@ -0,0 +1,150 @@
+package actors
+
+import (
+	"enewey.com/golang-game/colliders"
+	"enewey.com/golang-game/types"
+	"enewey.com/golang-game/utils"
+)
+
+// Carrier is an actor that can pick up another actor and carry it above its head.
+// A carried actor sits out of collisions, and follows its carrier around until it is thrown.
+type Carrier interface {
+	Carrying() CanMove
+	SetCarrying(CanMove)
+}
+
+var _ Carrier = &CharActor{}
+
+// Carrying - the actor being carried, or nil
+func (a *CharActor) Carrying() CanMove { return a.carrying }
+
+// SetCarrying - start carrying an actor, or stop carrying with nil
+func (a *CharActor) SetCarrying(c CanMove) { a.carrying = c }
+
+// speed of a thrown actor, along the XY plane and upwards
+const (
+	throwSpeed = 2.5
+	throwLift  = 2.5
+)
+
+// throwTimeout is how long a thrown actor can fly before it is let go of anyway,
+// e.g. when it lands in water, or is despawned mid-flight
+const throwTimeout = 240
+
+// liftable finds a light actor right in front of the subject, which it could pick up.
+// Light actors are lighter than the subject.
+func (m *Manager) liftable(subject CanMove) CanMove {
+	px, py := DirToVec(subject.Direction())
+	for _, c := range m.actorColliders.GetBlocking().GetColliding(px*4, py*4, 0, subject.Collider()) {
+		obj, ok := m.actors[c.Ref()].(CanMove)
+		if ok && obj.Weight() < subject.Weight() {
+			return obj
+		}
+	}
+	return nil
+}
+
+// carried maps the IDs of the actors being carried to their carriers
+func (m *Manager) carried() map[int]Carrier {
+	out := make(map[int]Carrier)
+	for _, a := range m.actors {
+		if carrier, ok := a.(Carrier); ok && carrier.Carrying() != nil {
+			out[carrier.Carrying().(Actor).ID()] = carrier
+		}
+	}
+	return out
+}
+
+// placeCarried puts a carried actor on top of its carrier's head
+func placeCarried(carrier, obj colliders.Collider) {
+	x, y, z := carrier.Pos()
+	ox, oy, _ := obj.Pos()
+	w, h, d := carrier.XDepth(y, z), carrier.YDepth(x, z), carrier.ZDepth(x, y)
+	ow, oh := obj.XDepth(oy, z+d), obj.YDepth(ox, z+d)
+	obj.SetPos(x+(w-ow)/2, y+(h-oh)/2, z+d)
+}
+
+// LiftAction picks up an actor, and starts carrying it.
+type LiftAction struct {
+	BaseAction
+	obj CanMove
+}
+
+// NewLiftAction creates an action for the target to pick up the object.
+func NewLiftAction(target Actor, obj CanMove) *LiftAction {
+	return &LiftAction{BaseAction{target, 0, 0}, obj}
+}
+
+// Process - lifts the object immediately
+func (a *LiftAction) Process(df types.Frame) bool {
+	carrier, ok := a.target.(Carrier)
+	if !ok || carrier.Carrying() != nil {
+		return true
+	}
+	// carried actors can't be controlled by anything but their carrier
+	if c, ok := a.obj.(Controllable); ok {
+		c.SetControlled(true)
+	}
+	a.obj.SetVel(0, 0, 0)
+	a.obj.SetSubPos(0, 0, 0)
+	a.obj.SetOnGround(false)
+	carrier.SetCarrying(a.obj)
+	placeCarried(a.target.Collider(), a.obj.Collider())
+	return true
+}
+
+// release hands control of a carried actor back, and lets it fall from where it is
+func release(obj CanMove) {
+	obj.SetOnGround(false)
+	if c, ok := obj.(Controllable); ok {
+		c.SetControlled(false)
+	}
+}
+
+// ThrowAction throws a carried actor in the direction the target faces.
+// The thrown actor flies in an arc under gravity, and the action completes when it lands,
+// or when it has flown for too long.
+type ThrowAction struct {
+	BaseAction
+	obj CanMove
+}
+
+// NewThrowAction creates an action for the target to throw whatever it is carrying.
+func NewThrowAction(target Actor) *ThrowAction {
+	return &ThrowAction{BaseAction{target, 0, 0}, nil}
+}
+
+// Process w
+func (a *ThrowAction) Process(df types.Frame) bool {
+	if a.obj == nil {
+		carrier, ok := a.target.(Carrier)
+		mover, isMover := a.target.(CanMove)
+		if !ok || !isMover || carrier.Carrying() == nil {
+			return true
+		}
+		a.obj = carrier.Carrying()
+		carrier.SetCarrying(nil)
+
+		vx, vy := utils.Normalize2(utils.Itof(DirToVec(mover.Direction())))
+		a.obj.SetDirection(mover.Direction())
+		m := a.obj.Mass()
+		a.obj.AddImpulse(vx*throwSpeed*m, vy*throwSpeed*m, throwLift*m)
+		a.obj.SetOnGround(false)
+		a.elapsed += df
+		return false
+	}
+
+	a.elapsed += df
+	if a.elapsed >= throwTimeout {
+		release(a.obj)
+		return true
+	}
+	if !a.obj.OnGround() {
+		return false
+	}
+	stop(a.obj)
+	if c, ok := a.obj.(Controllable); ok {
+		c.SetControlled(false)
+	}
+	return true
+}
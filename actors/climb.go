@@ -0,0 +1,107 @@
+package actors
+
+import (
+	"enewey.com/golang-game/colliders"
+	"enewey.com/golang-game/config"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/input"
+	"enewey.com/golang-game/sprites"
+)
+
+// Climbing characters move along Z instead of Y, and aren't pulled down by gravity.
+// A character grabs on to a climbable collider it is touching by pressing up, and lets go
+// by jumping, by climbing down to the ground, or by climbing off the end of it.
+
+// climbing speeds
+const (
+	climbSpeed = 1.0
+	climbJump  = 2.5 // jump velocity when letting go
+	ledgeHop   = 2.0 // vertical velocity when climbing off the top onto a ledge
+)
+
+// Climber is an actor that can climb climbable colliders
+type Climber interface {
+	CanClimb() bool
+}
+
+var _ Climber = &CharActor{}
+
+// CanClimb tells whether the character is touching something it can climb
+func (a *CharActor) CanClimb() bool { return a.touchingClimbable }
+
+// climbing tells whether the actor is currently climbing
+func climbing(a interface{}) bool {
+	stater, ok := a.(Stateful)
+	return ok && stater.State() == StateClimb
+}
+
+// touchingClimbable tells whether the subject is inside of, or right up against
+// (in the direction it faces), a climbable collider
+func touchingClimbable(subject CanMove, colliderCtx colliders.Colliders) bool {
+	climbables := colliderCtx.GetClimbable()
+	if len(climbables) == 0 {
+		return false
+	}
+	px, py := DirToVec(subject.Direction())
+	return len(climbables.GetColliding(0, 0, 0, subject.Collider())) > 0 ||
+		len(climbables.GetColliding(px, py, 0, subject.Collider())) > 0
+}
+
+// climb - player controls while climbing
+func climb(target Actor, state input.Input) bool {
+	cfg := config.Get()
+	player := target.(CanMove)
+
+	var dx, dz float64
+	if state[cfg.KeyUp()].Pressed() {
+		dz++
+	}
+	if state[cfg.KeyDown()].Pressed() {
+		dz--
+	}
+	if state[cfg.KeyLeft()].Pressed() {
+		dx--
+	}
+	if state[cfg.KeyRight()].Pressed() {
+		dx++
+	}
+	// the climber keeps facing whatever it is climbing
+	player.Steer(dx*climbSpeed, 0, dz*climbSpeed)
+
+	if state[cfg.KeyJump()].JustPressed() {
+		events.Enqueue(NewJumpEvent(target, climbJump))
+	}
+	return true
+}
+
+// updateClimb lets go of whatever the character is climbing, once it reaches the ground or the end.
+func (a *CharActor) updateClimb() {
+	switch {
+	case !a.touchingClimbable && a.vz > 0:
+		// climbed off the top; hop up on to the ledge
+		px, py := DirToVec(a.direction)
+		a.AddImpulse((float64(px)-a.vx)*a.mass, (float64(py)-a.vy)*a.mass, (ledgeHop-a.vz)*a.mass)
+		a.SetState(StateJump)
+	case !a.touchingClimbable:
+		a.SetState(StateFall)
+	case a.OnGround() && a.vz <= 0:
+		a.SetState(StateIdle)
+	}
+}
+
+// SetStateSpritemap sets a spritemap to draw the character with while it is in a state,
+// e.g. a climbing animation.
+func (a *CharActor) SetStateSpritemap(s CharState, sm sprites.Spritemap) {
+	if a.stateSprites == nil {
+		a.stateSprites = make(map[CharState]sprites.Spritemap)
+	}
+	a.stateSprites[s] = sm
+}
+
+// currentSpritemap is the spritemap for the character's current state
+func (a *CharActor) currentSpritemap() sprites.Spritemap {
+	if sm, ok := a.stateSprites[a.State()]; ok {
+		return sm
+	}
+	return a.spritemap
+}
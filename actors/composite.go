@@ -0,0 +1,182 @@
+package actors
+
+import (
+	"enewey.com/golang-game/types"
+)
+
+// This file contains composite actions, which combine other actions into a tree.
+// Composite actions have no target of their own; their children do.
+//
+// e.g. "hop, pause, slide two tiles, show message" as a single action:
+//
+//	NewSequenceAction(
+//		NewHopAction(a, 0, 0, 0, 8, 20, utils.Linear),
+//		NewWaitAction(15),
+//		NewMoveByAction(a, 32, 0, 0, 32),
+//		NewCallFuncAction(func() { events.Enqueue(msg) }),
+//	)
+
+// SequenceAction processes its children one after the other.
+// A child is started on the frame after the previous child completes.
+type SequenceAction struct {
+	BaseAction
+	children []Action
+	current  int
+}
+
+// NewSequenceAction creates an action that processes the children in order.
+func NewSequenceAction(children ...Action) *SequenceAction {
+	return &SequenceAction{BaseAction{nil, 0, 0}, children, 0}
+}
+
+// Process w
+func (a *SequenceAction) Process(df types.Frame) bool {
+	a.elapsed += df
+	if a.current >= len(a.children) {
+		return true
+	}
+	if child := a.children[a.current]; child == nil || child.Process(df) {
+		a.current++
+	}
+	return a.current >= len(a.children)
+}
+
+// ParallelAction processes all of its children at once, and completes when they all have.
+type ParallelAction struct {
+	BaseAction
+	children []Action
+	done     []bool
+}
+
+// NewParallelAction creates an action that processes the children side by side.
+func NewParallelAction(children ...Action) *ParallelAction {
+	return &ParallelAction{BaseAction{nil, 0, 0}, children, make([]bool, len(children))}
+}
+
+// Process w
+func (a *ParallelAction) Process(df types.Frame) bool {
+	a.elapsed += df
+	finished := true
+	for i, child := range a.children {
+		if a.done[i] || child == nil {
+			continue
+		}
+		a.done[i] = child.Process(df)
+		finished = finished && a.done[i]
+	}
+	return finished
+}
+
+// WaitAction does nothing for a number of frames.
+type WaitAction struct {
+	BaseAction
+}
+
+// NewWaitAction creates an action that completes after the number of frames.
+func NewWaitAction(duration types.Frame) *WaitAction {
+	return &WaitAction{BaseAction{nil, duration, 0}}
+}
+
+// Process w
+func (a *WaitAction) Process(df types.Frame) bool {
+	a.elapsed += df
+	return a.elapsed >= a.duration
+}
+
+// RepeatAction processes a freshly made action over and over.
+// Actions hold their own progress, so a factory is needed to make a new one for each repetition.
+type RepeatAction struct {
+	BaseAction
+	factory func() Action
+	times   int // negative repeats forever
+	count   int
+	current Action
+}
+
+// NewRepeatAction creates an action that processes a new action from the factory a number of times.
+func NewRepeatAction(factory func() Action, times int) *RepeatAction {
+	return &RepeatAction{BaseAction{nil, 0, 0}, factory, times, 0, nil}
+}
+
+// NewForeverAction creates an action that processes a new action from the factory forever.
+func NewForeverAction(factory func() Action) *RepeatAction {
+	return NewRepeatAction(factory, -1)
+}
+
+// Process w
+func (a *RepeatAction) Process(df types.Frame) bool {
+	a.elapsed += df
+	if a.times >= 0 && a.count >= a.times {
+		return true
+	}
+	if a.current == nil {
+		a.current = a.factory()
+	}
+	if a.current == nil || a.current.Process(df) {
+		a.current = nil
+		a.count++
+	}
+	return a.times >= 0 && a.count >= a.times
+}
+
+// WaitUntilAction does nothing until the predicate is true.
+type WaitUntilAction struct {
+	BaseAction
+	predicate func() bool
+}
+
+// NewWaitUntilAction creates an action that completes once the predicate returns true.
+func NewWaitUntilAction(predicate func() bool) *WaitUntilAction {
+	return &WaitUntilAction{BaseAction{nil, 0, 0}, predicate}
+}
+
+// Process w
+func (a *WaitUntilAction) Process(df types.Frame) bool {
+	a.elapsed += df
+	return a.predicate()
+}
+
+// IfAction processes one of two actions, chosen when it is first processed.
+type IfAction struct {
+	BaseAction
+	cond      func() bool
+	then, els Action
+	chosen    Action
+	hasChosen bool
+}
+
+// NewIfAction creates an action that processes "then" if the condition is true, and "els" otherwise.
+// Either action may be nil.
+func NewIfAction(cond func() bool, then, els Action) *IfAction {
+	return &IfAction{BaseAction{nil, 0, 0}, cond, then, els, nil, false}
+}
+
+// Process w
+func (a *IfAction) Process(df types.Frame) bool {
+	a.elapsed += df
+	if !a.hasChosen {
+		a.chosen = a.els
+		if a.cond() {
+			a.chosen = a.then
+		}
+		a.hasChosen = true
+	}
+	return a.chosen == nil || a.chosen.Process(df)
+}
+
+// CallFuncAction calls a function, and completes immediately.
+type CallFuncAction struct {
+	BaseAction
+	f func()
+}
+
+// NewCallFuncAction creates an action that calls the function once.
+func NewCallFuncAction(f func()) *CallFuncAction {
+	return &CallFuncAction{BaseAction{nil, 0, 0}, f}
+}
+
+// Process w
+func (a *CallFuncAction) Process(df types.Frame) bool {
+	a.f()
+	return true
+}
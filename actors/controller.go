@@ -1,6 +1,9 @@
 package actors
 
 import (
+	"math"
+
+	"enewey.com/golang-game/colliders"
 	"enewey.com/golang-game/config"
 	"enewey.com/golang-game/events"
 	"enewey.com/golang-game/input"
@@ -22,29 +25,42 @@ type Controller interface {
 	SetTarget(t int)
 }
 
-// PlayerController w
+// PlayerController moves the player according to the movement profile in the config.
+// Jumps and dashes pressed a little too early are buffered, and a jump is still allowed
+// for a few frames after walking off a ledge.
 type PlayerController struct {
 	controller
+	air      types.Frame // frames since the player was last on the ground
+	jumpBuf  types.Frame // frames left on a buffered jump
+	dashBuf  types.Frame // frames left on a buffered dash
+	jumping  bool        // rising from a jump, which can be cut short
+	jumpUsed bool        // jumped since last on the ground, so no coyote jump
 }
 
 // NewPlayerController t
 func NewPlayerController() *PlayerController {
-	return &PlayerController{controller{-1}}
+	return &PlayerController{controller{-1}, 0, 0, 0, false, false}
 }
 
 // Tap w
 func (c *PlayerController) Tap(target Actor, state input.Input, df types.Frame) bool {
-	return controlPlayer(target, state)
+	return c.controlPlayer(target, state, df)
 }
 
-func controlPlayer(target Actor, state input.Input) bool {
+func (c *PlayerController) controlPlayer(target Actor, state input.Input, df types.Frame) bool {
 	cfg := config.Get()
+	mv := cfg.Movement()
 	player := target.(CanMove)
+	stater, stateful := target.(Stateful)
 
-	if player.OnGround() {
-		_, _, vz := player.Vel()
-		player.SetVel(0, 0, vz)
+	c.buffer(player, state, mv, df)
+	if stateful && stater.State().Locked() {
+		return true
 	}
+	if climbing(target) {
+		return climb(target, state)
+	}
+
 	var dx, dy float64
 	if state[cfg.KeyUp()].Pressed() {
 		dy--
@@ -58,25 +74,107 @@ func controlPlayer(target Actor, state input.Input) bool {
 	if state[cfg.KeyRight()].Pressed() {
 		dx++
 	}
-	player.SetVelX(dx)
-	player.SetVelY(dy)
-	player.CalcDirection()
+	// the movement profile and the surface underfoot decide how quickly the player gets up
+	// to speed, and slows down. dashing carries the player along regardless of input.
+	if !stateful || stater.State() != StateDash {
+		speed, accel, decel := walkRates(player, mv)
+		vx, vy, _ := player.Vel()
+		m := player.Mass()
+		player.AddImpulse((approach(vx, dx*speed, accel, decel)-vx)*m, (approach(vy, dy*speed, accel, decel)-vy)*m, 0)
+	}
+	if dx != 0 || dy != 0 {
+		player.SetDirection(VecToDir(dx, dy, player.Direction()))
+	}
+
+	if state[cfg.KeyMenu()].JustPressed() {
+		events.Enqueue(events.NewInventoryEvent())
+		return true
+	}
 
 	if state[cfg.KeyConfirm()].JustPressed() {
-		events.Enqueue(NewInteractEvent(target))
+		if carrier, ok := target.(Carrier); ok && carrier.Carrying() != nil {
+			events.Enqueue(NewThrowEvent(target))
+		} else {
+			events.Enqueue(NewInteractEvent(target))
+		}
 	}
 
-	if state[cfg.KeyJump()].JustPressed() && player.OnGround() {
-		events.Enqueue(NewJumpEvent(target, 3.5))
+	// grab on to something climbable, unless still rising from a jump
+	if climber, ok := target.(Climber); ok && climber.CanClimb() && state[cfg.KeyUp()].Pressed() &&
+		stateful && stater.State() != StateJump && stater.StateMachine().CanTransition(StateClimb) {
+		if carrier, ok := target.(Carrier); !ok || carrier.Carrying() == nil {
+			stater.SetState(StateClimb)
+			player.Steer(0, 0, climbSpeed)
+			return true
+		}
 	}
 
-	if state[cfg.KeyDash()].JustPressed() && !player.(CanDash).Dashed() && player.OnGround() {
+	if med := player.Medium(); med != nil && med.Swimmable && !player.OnGround() {
+		// swimming up replaces jumping
+		if state[cfg.KeyJump()].JustPressed() {
+			events.Enqueue(NewSwimEvent(target, swimStroke))
+		}
+		c.jumpBuf = 0
+	} else if c.jumpBuf > 0 && (player.OnGround() || (c.air <= mv.Coyote && !c.jumpUsed)) &&
+		(!stateful || stater.StateMachine().CanTransition(StateJump)) {
+		if player.OnGround() {
+			events.Enqueue(NewJumpEvent(target, mv.Jump))
+		} else {
+			events.Enqueue(NewCoyoteJumpEvent(target, mv.Jump))
+		}
+		c.jumpBuf = 0
+		c.jumping = true
+		c.jumpUsed = true
+	}
+
+	// letting go of jump early cuts the jump short
+	if _, _, vz := player.Vel(); c.jumping && !state[cfg.KeyJump()].Pressed() && vz > mv.JumpCut {
+		player.AddImpulse(0, 0, (mv.JumpCut-vz)*player.Mass())
+		c.jumping = false
+	}
+
+	if c.dashBuf > 0 && player.OnGround() &&
+		stateful && stater.StateMachine().CanTransition(StateDash) {
 		vx, vy := utils.Normalize2(utils.Itof(DirToVec(player.Direction())))
-		events.Enqueue(NewDashEvent(target, vx*2.5, vy*2.5, 0.0))
+		events.Enqueue(NewDashEvent(target, vx*mv.Dash, vy*mv.Dash, 0.0))
+		c.dashBuf = 0
 	}
 	return true
 }
 
+// buffer keeps track of buffered jumps and dashes, and of how long the player has been off the ground
+func (c *PlayerController) buffer(player CanMove, state input.Input, mv *config.Movement, df types.Frame) {
+	cfg := config.Get()
+	if player.OnGround() {
+		c.air = 0
+		c.jumpUsed = false
+	} else {
+		c.air += df
+	}
+	if _, _, vz := player.Vel(); vz <= 0 {
+		c.jumping = false
+	}
+
+	c.jumpBuf -= df
+	c.dashBuf -= df
+	if state[cfg.KeyJump()].JustPressed() {
+		c.jumpBuf = mv.JumpBuffer + 1
+	}
+	if state[cfg.KeyDash()].JustPressed() {
+		c.dashBuf = mv.DashBuffer + 1
+	}
+}
+
+// walkRates are the top speed, acceleration and deceleration of the player, given its movement
+// profile and the surface underfoot. A surface can only make the player slower to speed up or stop.
+func walkRates(player CanMove, mv *config.Movement) (float64, float64, float64) {
+	if !player.OnGround() {
+		return mv.Speed, mv.Accel * mv.AirControl, mv.Decel * mv.AirControl
+	}
+	mat := groundMaterial(player)
+	return mv.Speed * mat.Speed, math.Min(mv.Accel, mat.Accel), math.Min(mv.Decel, mat.Friction)
+}
+
 type statefulController struct {
 	controller
 	state map[string]interface{}
@@ -113,3 +211,25 @@ func (c *MoveSequenceController) Tap(target Actor, state input.Input, df types.F
 	}
 	return true
 }
+
+// groundMaterial is the material of the surface the actor is standing on
+func groundMaterial(a CanMove) *colliders.Material {
+	if g := a.Ground(); g != nil && g.Material() != nil {
+		return g.Material()
+	}
+	return colliders.DefaultMaterial
+}
+
+// approach changes a velocity towards a target velocity, speeding up at the accel rate and
+// slowing down at the decel rate.
+func approach(v, target, accel, decel float64) float64 {
+	rate := accel
+	if target == 0 || v*target < 0 {
+		rate = decel
+	}
+	v += (target - v) * math.Min(math.Max(rate, 0), 1)
+	if math.Abs(v-target) < 0.01 {
+		return target
+	}
+	return v
+}
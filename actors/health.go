@@ -0,0 +1,173 @@
+package actors
+
+import (
+	"enewey.com/golang-game/colliders"
+	"enewey.com/golang-game/types"
+)
+
+// DeathBehavior describes what happens to an actor when it runs out of health.
+type DeathBehavior int
+
+// Death behaviors
+const (
+	Despawn DeathBehavior = iota
+	Respawn
+)
+
+// flashRate is how many frames an invulnerable actor's sprite is shown or hidden at a time.
+const flashRate types.Frame = 4
+
+// Damageable is an interface for actors which may carry health.
+type Damageable interface {
+	Health() *Health
+	SetHealth(*Health)
+}
+
+// Hitter is an interface for actors which may carry hitboxes.
+type Hitter interface {
+	Hitboxes() []*Hitbox
+	AddHitbox(*Hitbox)
+}
+
+var _ Damageable = &baseActor{}
+var _ Hitter = &baseActor{}
+
+// Health - hit points for an actor, and the hurtbox where it can be hurt.
+// The hurtbox is separate from the actor's blocking collider, and follows the
+// actor around at an offset. If no hurtbox is set, the actor's collider is used.
+type Health struct {
+	hp, max      int
+	invuln       types.Frame // remaining frames of invulnerability
+	invulnFrames types.Frame // frames of invulnerability granted when damaged
+	hurtbox      colliders.Collider
+	ox, oy, oz   int
+	death        DeathBehavior
+	sx, sy, sz   int // respawn point
+	hasSpawn     bool
+}
+
+// NewHealth creates a new full health pool.
+func NewHealth(max int, invulnFrames types.Frame, death DeathBehavior) *Health {
+	return &Health{
+		hp: max, max: max,
+		invulnFrames: invulnFrames,
+		death:        death,
+	}
+}
+
+// HP - current hit points
+func (h *Health) HP() int { return h.hp }
+
+// Max - maximum hit points
+func (h *Health) Max() int { return h.max }
+
+// Dead tells whether the hit points are depleted
+func (h *Health) Dead() bool { return h.hp <= 0 }
+
+// Death returns what should happen to the actor when it dies
+func (h *Health) Death() DeathBehavior { return h.death }
+
+// Invulnerable tells whether damage will currently be ignored
+func (h *Health) Invulnerable() bool { return h.invuln > 0 }
+
+// Flashing tells whether the actor's sprite should be hidden this frame, as
+// a signal of invulnerability.
+func (h *Health) Flashing() bool {
+	return h.invuln > 0 && (h.invuln/flashRate)%2 == 1
+}
+
+// SetHurtbox sets the collider where the actor can be hurt, offset from the actor position.
+func (h *Health) SetHurtbox(c colliders.Collider, ox, oy, oz int) {
+	h.hurtbox = c
+	h.ox, h.oy, h.oz = ox, oy, oz
+}
+
+// SetRespawn sets the position a Respawn actor returns to when it dies.
+func (h *Health) SetRespawn(x, y, z int) {
+	h.sx, h.sy, h.sz = x, y, z
+	h.hasSpawn = true
+}
+
+// RespawnPoint returns the position the actor returns to when it dies.
+func (h *Health) RespawnPoint() (int, int, int) { return h.sx, h.sy, h.sz }
+
+// Damage removes hit points, and starts invulnerability. Negative amounts heal.
+// Returns false if the damage was ignored due to invulnerability.
+func (h *Health) Damage(amount int) bool {
+	if amount > 0 && h.Invulnerable() {
+		return false
+	}
+	h.hp -= amount
+	if h.hp > h.max {
+		h.hp = h.max
+	}
+	if amount > 0 {
+		h.invuln = h.invulnFrames
+	}
+	return true
+}
+
+// Revive restores the health pool to full.
+func (h *Health) Revive() {
+	h.hp = h.max
+	h.invuln = h.invulnFrames
+}
+
+func (h *Health) tick(df types.Frame) {
+	if h.invuln > 0 {
+		h.invuln -= df
+	}
+}
+
+// hurtboxFor gets the hurtbox of the owner actor, synced to its position.
+func (h *Health) hurtboxFor(owner Actor) colliders.Collider {
+	if h.hurtbox == nil {
+		return owner.Collider()
+	}
+	x, y, z := owner.Pos()
+	h.hurtbox.SetPos(x+h.ox, y+h.oy, z+h.oz)
+	return h.hurtbox
+}
+
+// Hitbox - a collider that deals damage to the hurtboxes of other actors it touches.
+// Like hurtboxes, hitboxes follow the actor around at an offset.
+type Hitbox struct {
+	collider   colliders.Collider
+	ox, oy, oz int
+	damage     int
+	knockback  float64
+}
+
+// NewHitbox creates a new hitbox that deals damage and knocks back what it hits.
+func NewHitbox(c colliders.Collider, ox, oy, oz int, damage int, knockback float64) *Hitbox {
+	return &Hitbox{c, ox, oy, oz, damage, knockback}
+}
+
+// Damage - how much damage this hitbox deals
+func (h *Hitbox) Damage() int { return h.damage }
+
+// Knockback - the speed at which this hitbox knocks back what it hits
+func (h *Hitbox) Knockback() float64 { return h.knockback }
+
+func (h *Hitbox) colliderFor(owner Actor) colliders.Collider {
+	x, y, z := owner.Pos()
+	h.collider.SetPos(x+h.ox, y+h.oy, z+h.oz)
+	return h.collider
+}
+
+// Health - returns the health of the actor, or nil if it has none
+func (a *baseActor) Health() *Health { return a.health }
+
+// SetHealth - gives the actor a health pool
+func (a *baseActor) SetHealth(h *Health) { a.health = h }
+
+// Hitboxes - returns the hitboxes of the actor
+func (a *baseActor) Hitboxes() []*Hitbox { return a.hitboxes }
+
+// AddHitbox - adds a damaging hitbox to the actor
+func (a *baseActor) AddHitbox(h *Hitbox) { a.hitboxes = append(a.hitboxes, h) }
+
+// flashing tells whether the actor should skip drawing this frame
+func (a *baseActor) flashing() bool {
+	return a.health != nil && a.health.Flashing()
+}
@@ -1,29 +1,70 @@
 package actors
 
 import (
+	"sort"
+
 	"enewey.com/golang-game/colliders"
+	"enewey.com/golang-game/input"
+	"enewey.com/golang-game/types"
+	"github.com/hajimehoshi/ebiten"
 )
 
 // Hooks are essentially a function that will be invoked ("tapped") potentially every single frame.
-// When the hook executes, and the parameters it receives, depend on the hook type.
-// Currently there are "PostCollision" hooks, which will are executed after all collisions have been resolved,
-// and the params they receive are all the relevant colliders in the scene.
-// Other hooks will work in similar ways.
+// When the hook executes, and the context it receives, depend on the hook type. A hook implements
+// the interface of every point of the frame it wants to be tapped at:
+//
+//	PreInput, PostInput         - around the controllers handling input
+//	PreAct, PostAct             - around the queued actions being processed
+//	PreCollision, PostCollision - around collisions being resolved
+//	OnSpawn, OnDespawn          - when an actor is added to or removed from the manager
+//	PreRender, PostRender       - around the actors being drawn
+//	OnWarp                      - when the player has been taken to another room
+//
+// Hooks with a lower priority are tapped first, and hooks of the same priority are tapped in the
+// order they were added.
 type Hooks struct {
-	PostCollision []PostCollisionHook
+	entries []*hookEntry
+}
+
+type hookEntry struct {
+	hook     Hook
+	priority int
+	removed  bool
+}
+
+// AddHook - add a hook to the Hooks structure with a priority. A hook added while the hooks are
+// being tapped isn't tapped until the next time around.
+func (hs *Hooks) AddHook(hook Hook, priority int) {
+	entries := make([]*hookEntry, len(hs.entries), len(hs.entries)+1)
+	copy(entries, hs.entries)
+	entries = append(entries, &hookEntry{hook, priority, false})
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].priority < entries[j].priority
+	})
+	hs.entries = entries
 }
 
-// AddHook - add a hook to the Hooks structure
-func (hs *Hooks) AddHook(hook Hook) {
-	if h, ok := hook.(PostCollisionHook); ok {
-		hs.PostCollision = append(hs.PostCollision, h)
+// RemoveHook - remove a hook from the Hooks structure. A hook removed while the hooks are
+// being tapped isn't tapped again.
+func (hs *Hooks) RemoveHook(hook Hook) {
+	kept := make([]*hookEntry, 0, len(hs.entries))
+	for _, e := range hs.entries {
+		if e.hook == hook {
+			e.removed = true
+			continue
+		}
+		kept = append(kept, e)
 	}
+	hs.entries = kept
 }
 
-// PostCollisionHook - hook that occurs after collisions have been checked and prevented
-type PostCollisionHook interface {
-	SetManager(*Manager)
-	Tap(colliders.Colliders)
+// each taps every hook still in place, in order
+func (hs *Hooks) each(tap func(Hook)) {
+	for _, e := range hs.entries {
+		if !e.removed {
+			tap(e.hook)
+		}
+	}
 }
 
 // Hook - The base Hook interface
@@ -39,20 +80,186 @@ func (h *baseHook) SetManager(m *Manager) {
 	h.manager = m
 }
 
-// ShadowHook - hook for making on actor act as another actor's shadow.
-type ShadowHook struct {
-	baseHook
-	shadow, subject Actor
+// InputContext is what input hooks are tapped with
+type InputContext struct {
+	State    input.Input
+	Frames   types.Frame
+	Captured bool // whether a controller captured the input; only known after input
+}
+
+// ActContext is what act hooks are tapped with
+type ActContext struct {
+	Frames types.Frame
+}
+
+// CollisionContext is what collision hooks are tapped with
+type CollisionContext struct {
+	Colliders colliders.Colliders // the colliders of every actor taking part in collisions
+}
+
+// ActorContext is what spawn and despawn hooks are tapped with
+type ActorContext struct {
+	Actor Actor
+}
+
+// RenderContext is what render hooks are tapped with
+type RenderContext struct {
+	Image            *ebiten.Image
+	OffsetX, OffsetY int // scroll offset; a world position x/y is drawn at x-OffsetX, y-OffsetY
+}
+
+// PreInputHook - hook that occurs before the controllers handle input
+type PreInputHook interface {
+	Hook
+	PreInput(*InputContext)
+}
+
+// PostInputHook - hook that occurs after the controllers handle input
+type PostInputHook interface {
+	Hook
+	PostInput(*InputContext)
+}
+
+// PreActHook - hook that occurs before the queued actions are processed
+type PreActHook interface {
+	Hook
+	PreAct(*ActContext)
+}
+
+// PostActHook - hook that occurs after the queued actions are processed
+type PostActHook interface {
+	Hook
+	PostAct(*ActContext)
+}
+
+// PreCollisionHook - hook that occurs before anything is moved or collided
+type PreCollisionHook interface {
+	Hook
+	PreCollision(*CollisionContext)
+}
+
+// PostCollisionHook - hook that occurs after collisions have been checked and prevented
+type PostCollisionHook interface {
+	Hook
+	PostCollision(*CollisionContext)
+}
+
+// SpawnHook - hook that occurs when an actor is added to the manager
+type SpawnHook interface {
+	Hook
+	OnSpawn(*ActorContext)
+}
+
+// DespawnHook - hook that occurs when an actor is removed from the manager
+type DespawnHook interface {
+	Hook
+	OnDespawn(*ActorContext)
+}
+
+// WarpHook - hook that occurs once the player has been put in another room
+type WarpHook interface {
+	Hook
+	OnWarp(*ActorContext)
+}
+
+// PreRenderHook - hook that occurs before the actors are drawn
+type PreRenderHook interface {
+	Hook
+	PreRender(*RenderContext)
+}
+
+// PostRenderHook - hook that occurs after the actors are drawn, e.g. for debug overlays
+type PostRenderHook interface {
+	Hook
+	PostRender(*RenderContext)
+}
+
+// --- tapping the hooks at each point of the frame
+
+func (hs *Hooks) preInput(ctx *InputContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(PreInputHook); ok {
+			t.PreInput(ctx)
+		}
+	})
+}
+
+func (hs *Hooks) postInput(ctx *InputContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(PostInputHook); ok {
+			t.PostInput(ctx)
+		}
+	})
+}
+
+func (hs *Hooks) preAct(ctx *ActContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(PreActHook); ok {
+			t.PreAct(ctx)
+		}
+	})
+}
+
+func (hs *Hooks) postAct(ctx *ActContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(PostActHook); ok {
+			t.PostAct(ctx)
+		}
+	})
+}
+
+func (hs *Hooks) preCollision(ctx *CollisionContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(PreCollisionHook); ok {
+			t.PreCollision(ctx)
+		}
+	})
+}
+
+func (hs *Hooks) postCollision(ctx *CollisionContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(PostCollisionHook); ok {
+			t.PostCollision(ctx)
+		}
+	})
+}
+
+func (hs *Hooks) onSpawn(ctx *ActorContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(SpawnHook); ok {
+			t.OnSpawn(ctx)
+		}
+	})
+}
+
+func (hs *Hooks) onDespawn(ctx *ActorContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(DespawnHook); ok {
+			t.OnDespawn(ctx)
+		}
+	})
+}
+
+func (hs *Hooks) onWarp(ctx *ActorContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(WarpHook); ok {
+			t.OnWarp(ctx)
+		}
+	})
 }
 
-// NewShadowHook creates a new shadow hook
-func NewShadowHook(shadow, subject Actor) *ShadowHook {
-	return &ShadowHook{baseHook{}, shadow, subject}
+func (hs *Hooks) preRender(ctx *RenderContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(PreRenderHook); ok {
+			t.PreRender(ctx)
+		}
+	})
 }
 
-// Tap - queues a change position action
-func (h *ShadowHook) Tap(colls colliders.Colliders) {
-	x, y, _ := h.subject.Pos()
-	floor := colls.GetBlocking().FindFloor(h.subject.Collider())
-	h.shadow.SetPos(x, y, floor+4.0)
+func (hs *Hooks) postRender(ctx *RenderContext) {
+	hs.each(func(h Hook) {
+		if t, ok := h.(PostRenderHook); ok {
+			t.PostRender(ctx)
+		}
+	})
 }
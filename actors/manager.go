@@ -6,9 +6,9 @@ import (
 	"sort"
 
 	"enewey.com/golang-game/colliders"
-	"enewey.com/golang-game/config"
 	"enewey.com/golang-game/events"
 	"enewey.com/golang-game/input"
+	"enewey.com/golang-game/sprites"
 	"enewey.com/golang-game/types"
 	"enewey.com/golang-game/utils"
 	"github.com/hajimehoshi/ebiten"
@@ -22,6 +22,11 @@ type Manager struct {
 	actorColliders colliders.Colliders
 	actions        Actions
 	hooks          *Hooks
+	nextID         int
+	shadowed       map[int]bool // actors that cast a shadow
+	shadowSprite   *sprites.Sprite
+	names          map[int]string
+	tags           map[int]map[string]bool
 
 	collState map[int]bool
 }
@@ -34,7 +39,12 @@ func NewManager() *Manager {
 		[]Actor{},
 		colliders.Colliders{},
 		make([]Action, 5),
-		&Hooks{[]PostCollisionHook{}},
+		&Hooks{},
+		0,
+		make(map[int]bool),
+		nil,
+		make(map[int]string),
+		make(map[int]map[string]bool),
 		nil,
 	}
 	return ret
@@ -44,10 +54,13 @@ func NewManager() *Manager {
 func (m *Manager) Actors() map[int]Actor { return m.actors }
 
 // Actions w
-func (m *Manager) Actions() Actions { return m.actions }
+func (m *Manager) Actions() *Actions { return &m.actions }
 
 // Act - process all queued actions
 func (m *Manager) Act(df types.Frame) {
+	ctx := &ActContext{df}
+	m.hooks.preAct(ctx)
+	defer m.hooks.postAct(ctx)
 	i := 0
 	for i < len(m.actions) {
 		action := m.actions[i]
@@ -61,10 +74,20 @@ func (m *Manager) Act(df types.Frame) {
 	}
 }
 
-// AddHook - add a hook to be processed by the manager
+// AddHook - add a hook to be processed by the manager, with the default priority of zero
 func (m *Manager) AddHook(hook Hook) {
+	m.AddHookWithPriority(hook, 0)
+}
+
+// AddHookWithPriority - add a hook to be processed by the manager; lower priorities are tapped first
+func (m *Manager) AddHookWithPriority(hook Hook, priority int) {
 	hook.SetManager(m)
-	m.hooks.AddHook(hook)
+	m.hooks.AddHook(hook, priority)
+}
+
+// RemoveHook - stop processing a hook
+func (m *Manager) RemoveHook(hook Hook) {
+	m.hooks.RemoveHook(hook)
 }
 
 // SetPlayer - set the player-controlled actor
@@ -74,6 +97,11 @@ func (m *Manager) SetPlayer(a Actor) {
 	m.setController(0, NewPlayerController())
 }
 
+// Warped taps the warp hooks, once the player has been put in another room
+func (m *Manager) Warped() {
+	m.hooks.onWarp(&ActorContext{m.GetPlayer()})
+}
+
 // GetPlayer returns a pointer to the actor whom is controlled by the player.
 func (m *Manager) GetPlayer() Actor {
 	return m.actors[0]
@@ -81,17 +109,44 @@ func (m *Manager) GetPlayer() Actor {
 
 // AddActor - add a new actor to the manager that has no controller
 func (m *Manager) AddActor(a Actor) {
-	a.SetID(len(m.actors) + 1)
+	m.nextID++
+	a.SetID(m.nextID)
 	m.setActor(a.ID(), a)
 }
 
 // AddActorWithController - add a new actor to the manager with a controller type
 func (m *Manager) AddActorWithController(a Actor, ctrl Controller) {
-	a.SetID(len(m.actors) + 1)
-	m.setActor(a.ID(), a)
+	m.AddActor(a)
 	m.setController(a.ID(), ctrl)
 }
 
+// RemoveActor - remove an actor (and its controller) from the manager
+func (m *Manager) RemoveActor(a Actor) {
+	if m.actors[a.ID()] != a {
+		return
+	}
+	delete(m.actors, a.ID())
+	delete(m.controllers, a.ID())
+	delete(m.shadowed, a.ID())
+	delete(m.names, a.ID())
+	delete(m.tags, a.ID())
+	if carrier, ok := m.carried()[a.ID()]; ok {
+		carrier.SetCarrying(nil)
+	}
+	if carrier, ok := a.(Carrier); ok && carrier.Carrying() != nil {
+		release(carrier.Carrying())
+		carrier.SetCarrying(nil)
+	}
+	for i, v := range m.sortedActors {
+		if v == a {
+			m.sortedActors = append(m.sortedActors[:i], m.sortedActors[i+1:]...)
+			break
+		}
+	}
+	m.actorColliders = m.actorColliders.ExcludeByCollider(a.Collider())
+	m.hooks.onDespawn(&ActorContext{a})
+}
+
 func (m *Manager) setActor(id int, a Actor) {
 	m.actors[id] = a
 	a.Collider().SetRef(id)
@@ -99,6 +154,10 @@ func (m *Manager) setActor(id int, a Actor) {
 	if a.CanCollide() {
 		m.actorColliders = append(m.actorColliders, a.Collider())
 	}
+	if d, ok := a.(Damageable); ok && d.Health() != nil && !d.Health().hasSpawn {
+		d.Health().SetRespawn(a.Pos())
+	}
+	m.hooks.onSpawn(&ActorContext{a})
 }
 
 func (m *Manager) setController(id int, ctrl Controller) {
@@ -110,6 +169,8 @@ func (m *Manager) setController(id int, ctrl Controller) {
 // HandleInput - returns "true" if input is captured, disallowing any other
 // 				 manager from handling the input.
 func (m *Manager) HandleInput(state input.Input, df int) bool {
+	ctx := &InputContext{state, df, false}
+	m.hooks.preInput(ctx)
 	ret := false
 	for id, ctrl := range m.controllers {
 		if m.actors[id] == nil {
@@ -120,6 +181,8 @@ func (m *Manager) HandleInput(state input.Input, df int) bool {
 			ret = true
 		}
 	}
+	ctx.Captured = ret
+	m.hooks.postInput(ctx)
 	return ret
 }
 
@@ -140,32 +203,138 @@ func (m *Manager) HandleInteraction(subject Actor) bool {
 		}
 		return true
 	}
+
+	// with nothing to interact with, try picking something up
+	if carrier, ok := subject.(Carrier); ok && carrier.Carrying() == nil {
+		if obj := m.liftable(subject.(CanMove)); obj != nil {
+			events.Enqueue(NewLiftEvent(subject, obj))
+			return true
+		}
+	}
 	return false
 }
 
+// HandleStateChange taps the state change reactions of an actor that transitioned states.
+func (m *Manager) HandleStateChange(subject Actor, from, to CharState) {
+	for _, r := range subject.Collider().Reactions().OnStateChange {
+		r.Tap(subject, from, to)
+	}
+}
+
 // ResolveCollisions - every CanMove actor being managed will check collision against
 //		the provided Colliders.
 // 		Also alters velocity of actors in the air for gravity.
 func (m *Manager) ResolveCollisions() {
 	m.collState = make(map[int]bool)
-	mcolls := colliders.Colliders{}
-	for _, act := range m.actors {
-		mcolls = append(mcolls, act.Collider())
-	}
+	carried := m.carried()
+	m.hooks.preCollision(&CollisionContext{m.colliding(carried)})
+	m.movePlatforms(carried)
+	m.moveProjectiles(carried)
+
+	// platforms and projectiles may have despawned things (or dropped what they carried)
+	carried = m.carried()
+	mcolls := m.colliding(carried)
 	for _, ac := range m.actors {
 		if _, ok := ac.(CanMove); !ok {
 			continue
 		}
-		if m.collState[ac.ID()] {
+		if m.collState[ac.ID()] || carried[ac.ID()] != nil {
 			continue
 		}
 		subject := ac.(CanMove)
 
+		// whatever stands on the subject moves as far as it actually moved
+		riders := m.riders(ac.Collider(), carried)
+		x, y, z := ac.Pos()
 		m.handleCollision(subject, mcolls)
 		m.collState[ac.ID()] = true
+		if len(riders) > 0 {
+			nx, ny, nz := ac.Pos()
+			if nx != x || ny != y || nz != z {
+				m.carryRiders(riders, nx-x, ny-y, nz-z, ac.Collider(), carried)
+			}
+		}
+	}
+	for id, carrier := range carried {
+		placeCarried(carrier.(Actor).Collider(), m.actors[id].Collider())
+	}
+	m.resolveHits()
+	m.pressSwitches()
+	m.hooks.postCollision(&CollisionContext{mcolls})
+}
+
+// colliding gathers the colliders of every actor taking part in collisions, which is all of them
+// but the ones being carried
+func (m *Manager) colliding(carried map[int]Carrier) colliders.Colliders {
+	mcolls := colliders.Colliders{}
+	for _, act := range m.actors {
+		if carried[act.ID()] == nil {
+			mcolls = append(mcolls, act.Collider())
+		}
+	}
+	return mcolls
+}
+
+// resolveHits checks every hitbox against every hurtbox, and queues up damage
+// for the actors that were hit. Actors are only hit by one hitbox per frame.
+func (m *Manager) resolveHits() {
+	ids := make([]int, 0, len(m.actors))
+	for id := range m.actors {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		subject, ok := m.actors[id].(Damageable)
+		if !ok || subject.Health() == nil {
+			continue
+		}
+		health := subject.Health()
+		health.tick(1)
+		if health.Dead() || health.Invulnerable() {
+			continue
+		}
+		hurtbox := colliders.Colliders{health.hurtboxFor(m.actors[id])}
+
+	hitters:
+		for _, hid := range ids {
+			hitter, ok := m.actors[hid].(Hitter)
+			if hid == id || !ok {
+				continue
+			}
+			for _, hb := range hitter.Hitboxes() {
+				if hurtbox.WouldCollide(0, 0, 0, hb.colliderFor(m.actors[hid])) {
+					events.Enqueue(NewDamageEvent(m.actors[id], m.actors[hid], hb.Damage(), hb.Knockback()))
+					break hitters
+				}
+			}
+		}
+	}
+}
+
+// HandleDeath despawns or respawns an actor that has run out of health.
+func (m *Manager) HandleDeath(subject Actor) {
+	d, ok := subject.(Damageable)
+	if !ok || d.Health() == nil {
+		return
 	}
-	for _, hook := range m.hooks.PostCollision {
-		hook.Tap(mcolls)
+	// whatever it was carrying gets dropped where it is
+	if carrier, ok := subject.(Carrier); ok && carrier.Carrying() != nil {
+		if c, ok := carrier.Carrying().(Controllable); ok {
+			c.SetControlled(false)
+		}
+		carrier.SetCarrying(nil)
+	}
+	switch d.Health().Death() {
+	case Respawn:
+		subject.SetPos(d.Health().RespawnPoint())
+		if mover, ok := subject.(CanMove); ok {
+			mover.SetVel(0, 0, 0)
+			mover.SetSubPos(0, 0, 0)
+		}
+		d.Health().Revive()
+	default:
+		m.RemoveActor(subject)
 	}
 }
 
@@ -175,22 +344,9 @@ func (m *Manager) handleCollision(subject CanMove, mcolls colliders.Colliders) {
 
 	dx, dy, dz := subject.Vel()
 
-	// For stuff on top of other stuff, make them move with 'em
-	if subject.OnGround() {
-		for _, block := range colliderCtx.GetBlocking().GetColliding(int(dx), int(dy), -1, subject.Collider()) {
-			bx, by, bz := block.Pos()
-			// sx,sy,sz := subject.Collider().Pos()
-			blockD := block.ZDepth(bx, by)
-			// subD := subject.Collider().ZDepth(sx,sy)
-			above := subject.Collider().Z() >= blockD+bz
-
-			if obj, ok := m.actors[block.Ref()].(CanMove); ok && above {
-				a, b, c := subject.Vel()
-				x, y, z := obj.Vel()
-				subject.SetVel(a+x, b+y, c+z)
-			}
-		}
-	}
+	m.updateVolume(subject, colliderCtx)
+	integrate(subject)
+	dx, dy, dz = subject.Vel()
 
 	// First, run subject against colliders with custom behavior (reactive colliders)
 	reactors := colliderCtx.GetReactive(events.ReactionOnCollision)
@@ -203,6 +359,12 @@ func (m *Manager) handleCollision(subject CanMove, mcolls colliders.Colliders) {
 
 	dx, dy, dz = subject.MoveDelta(subject.Vel())
 
+	// conveyors move whatever rests on them
+	if subject.OnGround() {
+		mat := groundMaterial(subject)
+		dx, dy = dx+mat.ConveyX, dy+mat.ConveyY
+	}
+
 	// Next, shove lighter things we run into, and force them to resolve collisions again.
 	// This needs diligent testing... a shoved collider needs to have its collisions handled again.
 	colliderCtx.GetBlocking().GetColliding(
@@ -241,6 +403,13 @@ func (m *Manager) handleCollision(subject CanMove, mcolls colliders.Colliders) {
 
 	// Second, check collision against blocking colliders and prevent the collisions.
 	handleBlockingCollisions(dx, dy, dz, subject, blockerCtx)
+
+	// now that the actor has landed (or not), let it resolve its state,
+	// judging what it's holding on to from where it ended up
+	if char, ok := subject.(*CharActor); ok {
+		char.touchingClimbable = touchingClimbable(subject, colliderCtx)
+		char.updateState(1)
+	}
 }
 
 // receives a movement delta, NOT a velocity.
@@ -317,32 +486,62 @@ func handleBlockingCollisions(dx, dy, dz float64, v CanMove, colliderCtx collide
 		v.SetOnGround(false)
 	}
 
-	// if the actor is in a "dashed" state,
-	// make sure it gets cleared when the actor hits the ground
-	if dasher, ok := v.(CanDash); ok && dasher.Dashed() {
-		dasher.SetDashed(!v.OnGround())
-	}
-
 	if hitC {
 		v.SetVelZ(0)
-	} else if !hitG && !v.OnGround() {
-		_, _, vz := v.Vel()
-		v.SetVelZ(math.Max(vz+config.Get().Gravity(), -6.0))
 	}
 
 	v.SetSubPos(utils.Carry(dx, dy, dz))
+
+	// report back what the actor is standing on
+	if mover, ok := v.(interface{ moving() *MovingActor }); ok {
+		mover.moving().ground = nil
+		if v.OnGround() {
+			mover.moving().ground = findGround(v.Collider(), colliderCtx)
+		}
+	}
+}
+
+// findGround finds the collider right underneath the subject, with the highest top
+func findGround(subject colliders.Collider, colliderCtx colliders.Colliders) colliders.Collider {
+	var ground colliders.Collider
+	top := 0
+	for _, c := range colliderCtx.GetColliding(0, 0, -1, subject) {
+		x, y, z := c.Pos()
+		if t := z + c.ZDepth(x, y); ground == nil || t > top {
+			ground, top = c, t
+		}
+	}
+	return ground
 }
 
 // Render - draw the actors given a priority and row
 func (m *Manager) Render(img *ebiten.Image, ox, oy int) *ebiten.Image {
+	ctx := &RenderContext{img, ox, oy}
+	m.hooks.preRender(ctx)
 	m.drawSort()
+	carried := m.carried()
+
+	// the floor goes down first, then the shadows on top of it, then everything else
+	for _, actor := range m.sortedActors {
+		if drawable, ok := actor.(Drawable); ok && flat(actor) && carried[actor.ID()] == nil {
+			drawable.draw(img, -ox, -oy)
+		}
+	}
+	m.drawShadows(img, carried, -ox, -oy)
 	for _, actor := range m.sortedActors {
 		drawable, ok := actor.(Drawable)
-		if !ok {
+		if !ok || carried[actor.ID()] != nil || flat(actor) {
 			continue
 		}
 		drawable.draw(img, -ox, -oy)
+		// carried actors are drawn right on top of their carriers
+		if carrier, ok := actor.(Carrier); ok && carrier.Carrying() != nil {
+			if d, ok := carrier.Carrying().(Drawable); ok {
+				d.draw(img, -ox, -oy)
+			}
+		}
 	}
+	m.hooks.postRender(ctx)
 	return img
 }
 
@@ -0,0 +1,216 @@
+package actors
+
+import (
+	"math"
+
+	"enewey.com/golang-game/config"
+	"enewey.com/golang-game/types"
+	"enewey.com/golang-game/utils"
+)
+
+// followSpeed is the fastest a follower moves to keep up with the trail
+const followSpeed = 3.0
+
+// trailPoint is where the party leader was on some frame
+type trailPoint struct {
+	x, y, z int
+	dir     types.Direction
+	jump    float64 // velocity of a jump the leader took off with on this frame, or zero
+}
+
+// Party is a hook that makes followers trail behind the player, each one replaying where the
+// player was a fixed number of steps before the one in front of it, jumps and facing included.
+// The trail only grows while the player moves, so a party that stops keeps its spacing.
+// When the player warps to another room, the followers are brought along, lined up behind it.
+// The swap key makes the first follower the player, sending the player to the back of the party.
+type Party struct {
+	baseHook
+	followers []*CharActor
+	spacing   int // steps of the trail between party members
+	trail     []trailPoint
+	grounded  bool // whether the leader was on the ground last frame
+}
+
+var _ PostCollisionHook = &Party{}
+var _ PreInputHook = &Party{}
+var _ WarpHook = &Party{}
+
+// NewParty creates a party whose members trail each other by the spacing, in steps the player takes
+func NewParty(spacing int) *Party {
+	if spacing < 1 {
+		spacing = 1
+	}
+	return &Party{baseHook{}, nil, spacing, nil, true}
+}
+
+// AddFollower adds a character to the back of the party
+func (p *Party) AddFollower(a *CharActor) {
+	p.followers = append(p.followers, a)
+}
+
+// Followers lists the party members behind the player, front to back
+func (p *Party) Followers() []*CharActor { return p.followers }
+
+// PreInput swaps the leader when the swap key is pressed
+func (p *Party) PreInput(ctx *InputContext) {
+	if ctx.State[config.Get().KeySwap()].JustPressed() {
+		p.Swap()
+	}
+}
+
+// Swap makes the first follower the player, and sends the player to the back of the party
+func (p *Party) Swap() {
+	if p.manager == nil || len(p.followers) == 0 {
+		return
+	}
+	leader, ok := p.manager.GetPlayer().(*CharActor)
+	if !ok {
+		return
+	}
+	// no swapping out of (or into) a cutscene, a hit, or anything else that locks a character
+	next := p.followers[0]
+	if leader.State().Locked() || next.State().Locked() {
+		return
+	}
+	p.manager.SwapPlayer(next)
+	stop(leader)
+	p.followers = append(p.followers[1:], leader)
+	p.teleport(false)
+}
+
+// PostCollision records where the player went, and moves the followers along the trail
+func (p *Party) PostCollision(ctx *CollisionContext) {
+	leader, ok := p.manager.GetPlayer().(CanMove)
+	if !ok {
+		return
+	}
+	x, y, z := leader.Collider().Pos()
+
+	point := trailPoint{x, y, z, leader.Direction(), 0}
+	if _, _, vz := leader.Vel(); p.grounded && !leader.OnGround() && vz > 0 {
+		point.jump = vz
+	}
+	p.grounded = leader.OnGround()
+	if n := len(p.trail); n == 0 || point.jump > 0 || p.trail[n-1].x != x || p.trail[n-1].y != y || p.trail[n-1].z != z {
+		p.trail = append(p.trail, point)
+	}
+	if max := p.spacing*len(p.followers) + 1; len(p.trail) > max {
+		p.trail = p.trail[len(p.trail)-max:]
+	}
+
+	for i, f := range p.followers {
+		if p.manager.Actors()[f.ID()] != Actor(f) {
+			continue
+		}
+		at := len(p.trail) - 1 - (i+1)*p.spacing
+		if at < 0 {
+			// the player hasn't gone far enough for this follower to move yet
+			stop(f)
+			continue
+		}
+		p.follow(f, p.trail[at])
+	}
+}
+
+// follow steers a follower to a point of the trail
+func (p *Party) follow(f *CharActor, point trailPoint) {
+	x, y, _ := f.Pos()
+	dx, dy := float64(point.x-x), float64(point.y-y)
+	if dist := utils.Magnitude2(dx, dy); dist > followSpeed {
+		dx, dy = dx/dist*followSpeed, dy/dist*followSpeed
+	}
+	drive(f, dx, dy)
+	f.SetDirection(point.dir)
+	if point.jump > 0 && f.OnGround() {
+		f.AddImpulse(0, 0, (point.jump-math.Max(f.vz, 0))*f.Mass())
+		f.SetOnGround(false)
+		f.SetState(StateJump)
+	}
+}
+
+// OnWarp brings the followers along to wherever the player warped to
+func (p *Party) OnWarp(ctx *ActorContext) {
+	p.teleport(true)
+}
+
+// Gather lines the followers up behind the player, e.g. once the party has been set up
+func (p *Party) Gather() {
+	if p.manager != nil {
+		p.teleport(true)
+	}
+}
+
+// teleport lines every follower up behind the player, and starts the trail over from there
+func (p *Party) teleport(move bool) {
+	leader := p.manager.GetPlayer()
+	if move {
+		p.lineUp(leader)
+	}
+	p.trail = nil
+	if mover, ok := leader.(CanMove); ok {
+		p.grounded = mover.OnGround()
+	}
+}
+
+// lineUp puts the followers in a line behind the leader, one after another. Party members block
+// each other like anything else, so a follower goes beside (or failing that, in front of) the
+// spot behind the one in front of it if that spot isn't clear.
+func (p *Party) lineUp(leader Actor) {
+	dir := types.Down
+	if mover, ok := leader.(CanMove); ok {
+		dir = mover.Direction()
+	}
+	dx, dy := DirToVec(dir)
+	if dx == 0 && dy == 0 {
+		dy = 1
+	}
+	world := p.manager.world(p.manager.carried())
+	prev := leader
+	for _, f := range p.followers {
+		if p.manager.Actors()[f.ID()] != Actor(f) {
+			continue
+		}
+		x, y, z := prev.Pos()
+		fx, fy, _ := f.Pos()
+		w, h, _ := f.Collider().Center()
+		gap := 2*utils.Max(w-fx, h-fy) + 2
+		others := world.ExcludeByCollider(f.Collider())
+
+		spots := [][2]int{{-dx, -dy}, {-dy, dx}, {dy, -dx}, {dx, dy}}
+		placed := false
+		for _, s := range spots {
+			f.SetPos(x+s[0]*gap, y+s[1]*gap, z)
+			if !others.WouldCollide(0, 0, 0, f.Collider()) {
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			f.SetPos(x-dx*gap, y-dy*gap, z)
+		}
+		f.SetVel(0, 0, 0)
+		f.SetSubPos(0, 0, 0)
+		f.SetDirection(dir)
+		prev = f
+	}
+}
+
+// SwapPlayer makes another actor of the manager the player-controlled actor 0, giving the old player
+// the other actor's ID. The player controller stays with actor 0. Anything that means to act on the
+// player should look it up with GetPlayer (or a TargetPlayer) when it acts, rather than hold on to it.
+func (m *Manager) SwapPlayer(a Actor) {
+	id := a.ID()
+	old := m.actors[0]
+	if m.actors[id] != a || id == 0 || old == nil {
+		return
+	}
+	m.actors[0], m.actors[id] = a, old
+	a.SetID(0)
+	old.SetID(id)
+	a.Collider().SetRef(0)
+	old.Collider().SetRef(id)
+
+	m.shadowed[0], m.shadowed[id] = m.shadowed[id], m.shadowed[0]
+	m.names[0], m.names[id] = m.names[id], m.names[0]
+	m.tags[0], m.tags[id] = m.tags[id], m.tags[0]
+}
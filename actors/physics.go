@@ -0,0 +1,116 @@
+package actors
+
+import (
+	"enewey.com/golang-game/config"
+	"enewey.com/golang-game/types"
+)
+
+// Rather than setting the velocity of a moving actor outright, things that push actors around
+// add forces (accelerations scaled by mass, applied for a frame) and impulses (instant changes of
+// velocity scaled by mass). Everything added over a frame is integrated into the actor's velocity
+// once per frame, during ResolveCollisions, along with gravity and drag.
+//
+// Actions that move an actor along an exact path (tweens, dashes, climbing) steer it instead.
+// Steering sets the velocity along some axes for a frame, overriding everything else added to
+// those axes, so e.g. a controller walking the actor around can't slow down a scripted move.
+//
+// Platforms carry their riders positionally instead, on purpose: a rider is moved exactly as far
+// as the platform moved, before it integrates its own velocity, so it never drifts off or lags
+// behind. Its own velocity (walking around on the platform) is left untouched by the carry.
+
+// maxFall is how fast actors fall through the open air
+const maxFall = 6.0
+
+// Mass - the mass of the actor, which resists forces and impulses
+func (a *MovingActor) Mass() float64 { return a.mass }
+
+// SetMass - sets the mass of the actor. Mass must be positive.
+func (a *MovingActor) SetMass(m float64) {
+	if m > 0 {
+		a.mass = m
+	}
+}
+
+// Drag - the portion of its velocity the actor loses each frame
+func (a *MovingActor) Drag() float64 { return a.drag }
+
+// SetDrag - sets the portion of its velocity the actor loses each frame (0 to 1)
+func (a *MovingActor) SetDrag(d float64) { a.drag = d }
+
+// AddForce - push the actor for the current frame
+func (a *MovingActor) AddForce(x, y, z float64) {
+	a.fx, a.fy, a.fz = a.fx+x, a.fy+y, a.fz+z
+}
+
+// AddImpulse - instantly change the actor's momentum
+func (a *MovingActor) AddImpulse(x, y, z float64) {
+	a.ix, a.iy, a.iz = a.ix+x, a.iy+y, a.iz+z
+}
+
+// Steer - drive the actor at a velocity for the current frame, whatever else acts on it
+func (a *MovingActor) Steer(x, y, z float64) {
+	a.SteerAxes(&types.AxisMap{X: 1, Y: 1, Z: 1}, x, y, z)
+}
+
+// SteerAxes - drive the actor at a velocity for the current frame, only along the axes flagged in the map.
+// The other axes are left to forces and impulses.
+func (a *MovingActor) SteerAxes(axes *types.AxisMap, x, y, z float64) {
+	if a.steering == nil {
+		a.steering = &types.AxisMap{}
+	}
+	if axes.IsX() {
+		a.steering.X, a.sx = 1, x
+	}
+	if axes.IsY() {
+		a.steering.Y, a.sy = 1, y
+	}
+	if axes.IsZ() {
+		a.steering.Z, a.sz = 1, z
+	}
+}
+
+// integrate applies everything accumulated over the frame to the actor's velocity, and clears it.
+// Gravity only pulls on falling actors.
+func (a *MovingActor) integrate(df float64, falling bool) {
+	g, fall, drag := config.Get().Gravity(), maxFall, a.drag
+	if med := a.Medium(); med != nil {
+		g, fall = g*med.Gravity, med.MaxFall
+		drag = 1 - (1-drag)*(1-med.Drag)
+		a.AddForce(med.WindX, med.WindY, med.WindZ)
+		if a.weight <= med.MaxFloat {
+			a.AddForce(0, 0, med.Buoyancy*a.mass)
+		}
+	}
+	if falling {
+		a.AddForce(0, 0, g*a.mass)
+	}
+
+	a.vx += (a.fx*df + a.ix) / a.mass
+	a.vy += (a.fy*df + a.iy) / a.mass
+	a.vz += (a.fz*df + a.iz) / a.mass
+	a.vx, a.vy, a.vz = a.vx*(1-drag), a.vy*(1-drag), a.vz*(1-drag)
+	if a.vz < -fall {
+		a.vz = -fall
+	}
+	if s := a.steering; s != nil {
+		if s.IsX() {
+			a.vx = a.sx
+		}
+		if s.IsY() {
+			a.vy = a.sy
+		}
+		if s.IsZ() {
+			a.vz = a.sz
+		}
+	}
+	a.fx, a.fy, a.fz = 0, 0, 0
+	a.ix, a.iy, a.iz = 0, 0, 0
+	a.steering = nil
+}
+
+// integrate the forces and impulses on the subject, before it moves
+func integrate(subject CanMove) {
+	if mover, ok := subject.(interface{ moving() *MovingActor }); ok {
+		mover.moving().integrate(1, !subject.OnGround() && !climbing(subject))
+	}
+}
@@ -0,0 +1,258 @@
+package actors
+
+import (
+	"math"
+	"sort"
+
+	"enewey.com/golang-game/colliders"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/sprites"
+	"enewey.com/golang-game/types"
+	"enewey.com/golang-game/utils"
+)
+
+// Waypoint is a point along a platform's path, and how long the platform waits there.
+type Waypoint struct {
+	X, Y, Z int
+	Wait    types.Frame
+}
+
+// PlatformActor is a blocking actor that moves along a path of waypoints.
+// Platforms aren't pushed around by anything; they carry the actors riding on top of them,
+// and push (or crush) the actors they run into.
+type PlatformActor struct {
+	StaticActor
+	path       []Waypoint
+	speed      float64
+	loop       bool // loop back to the first waypoint, rather than reversing
+	crush      int  // damage dealt to actors crushed by the platform
+	active     bool
+	current    int
+	step       int
+	ticks      types.Frame // frames left waiting at a waypoint
+	fx, fy, fz float64     // exact position
+	halt       bool        // stop at the current waypoint, rather than carrying on along the path
+}
+
+// NewPlatformActor creates a platform that travels the path at the given speed, starting from
+// wherever its collider is. When looping, the platform goes from the last waypoint back to
+// the first; otherwise, it goes through the waypoints in reverse.
+func NewPlatformActor(
+	category string,
+	sprite sprites.Spritemap,
+	collider colliders.Collider,
+	ox, oy int,
+	path []Waypoint,
+	speed float64,
+	loop bool,
+	crush int,
+) *PlatformActor {
+	x, y, z := collider.Pos()
+	return &PlatformActor{
+		*NewStaticActor(category, sprite, collider, ox, oy),
+		path, speed, loop, crush, true,
+		0, 1, 0,
+		float64(x), float64(y), float64(z),
+		false,
+	}
+}
+
+// Active tells whether the platform is moving along its path
+func (p *PlatformActor) Active() bool { return p.active }
+
+// SetActive starts or stops the platform
+func (p *PlatformActor) SetActive(b bool) { p.active = b }
+
+// Travel sends the platform to one of its waypoints, where it stops, e.g. to open or close a door
+func (p *PlatformActor) Travel(i int) {
+	if i < 0 || i >= len(p.path) {
+		return
+	}
+	p.current, p.ticks = i, 0
+	p.active, p.halt = true, true
+}
+
+// SetPos - sets the position of the platform, which carries on along its path from there
+func (p *PlatformActor) SetPos(x, y, z int) {
+	p.collider.SetPos(x, y, z)
+	p.fx, p.fy, p.fz = float64(x), float64(y), float64(z)
+}
+
+// plan works out where the platform would be after this frame, without moving it.
+// Returns the exact position, and whether the platform arrives at the current waypoint.
+func (p *PlatformActor) plan(df types.Frame) (float64, float64, float64, bool) {
+	if !p.active || len(p.path) == 0 || p.ticks > 0 {
+		return p.fx, p.fy, p.fz, false
+	}
+	wp := p.path[p.current]
+	dx, dy, dz := utils.Sub3(float64(wp.X), float64(wp.Y), float64(wp.Z), p.fx, p.fy, p.fz)
+	dist := utils.Magnitude3(dx, dy, dz)
+	travel := p.speed * float64(df)
+	if dist <= travel {
+		return float64(wp.X), float64(wp.Y), float64(wp.Z), true
+	}
+	return p.fx + dx/dist*travel, p.fy + dy/dist*travel, p.fz + dz/dist*travel, false
+}
+
+// commit moves the platform to a planned position, and heads for the next waypoint if it arrived.
+func (p *PlatformActor) commit(x, y, z float64, arrived bool, df types.Frame) {
+	if !p.active {
+		return
+	}
+	if p.ticks > 0 {
+		p.ticks -= df
+		return
+	}
+	p.fx, p.fy, p.fz = x, y, z
+	p.collider.SetPos(round3(x, y, z))
+	if !arrived {
+		return
+	}
+	if p.halt {
+		p.active, p.halt = false, false
+		return
+	}
+
+	p.ticks = p.path[p.current].Wait
+	if len(p.path) == 1 {
+		return
+	}
+	next := p.current + p.step
+	if next < 0 || next >= len(p.path) {
+		if p.loop {
+			next = 0
+		} else {
+			p.step *= -1
+			next = p.current + p.step
+		}
+	}
+	p.current = next
+}
+
+func round3(x, y, z float64) (int, int, int) {
+	return int(math.Round(x)), int(math.Round(y)), int(math.Round(z))
+}
+
+// movePlatforms moves every platform a frame along its path, bringing riders along and
+// pushing aside anything in the way. A platform that can't push something out of the way
+// crushes it, and waits for it to get out of the way.
+func (m *Manager) movePlatforms(carried map[int]Carrier) {
+	ids := make([]int, 0)
+	for id, a := range m.actors {
+		if _, ok := a.(*PlatformActor); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		p := m.actors[id].(*PlatformActor)
+		nx, ny, nz, arrived := p.plan(1)
+		x, y, z := p.Pos()
+		tx, ty, tz := round3(nx, ny, nz)
+		dx, dy, dz := tx-x, ty-y, tz-z
+		if dx == 0 && dy == 0 && dz == 0 {
+			p.commit(nx, ny, nz, arrived, 1)
+			continue
+		}
+
+		riders := m.riders(p.Collider(), carried)
+		p.Collider().Translate(dx, dy, dz)
+		moved, stuck := m.carryRiders(riders, dx, dy, dz, p.Collider(), carried)
+
+		// push whatever the platform ran into
+		blocked := false
+		var pushed []colliders.Collider
+		world := m.world(carried).ExcludeByCollider(p.Collider())
+		for _, c := range world.GetColliding(0, 0, 0, p.Collider()) {
+			mover, ok := m.actors[c.Ref()].(CanMove)
+			if !ok {
+				blocked = true
+				continue
+			}
+			if world.ExcludeByCollider(c).WouldCollide(dx, dy, dz, c) {
+				m.crush(mover, p)
+				blocked = true
+				continue
+			}
+			c.Translate(dx, dy, dz)
+			pushed = append(pushed, c)
+		}
+		// a rider that can't rise with the platform is pinned against the ceiling
+		if dz > 0 {
+			for _, r := range stuck {
+				m.crush(r, p)
+				blocked = true
+			}
+		}
+
+		if blocked {
+			p.Collider().Translate(-dx, -dy, -dz)
+			for _, r := range moved {
+				r.Collider().Translate(-dx, -dy, -dz)
+			}
+			for _, c := range pushed {
+				c.Translate(-dx, -dy, -dz)
+			}
+			continue
+		}
+		p.commit(nx, ny, nz, arrived, 1)
+	}
+}
+
+// crush deals the platform's crush damage to an actor caught by it
+func (m *Manager) crush(a CanMove, p *PlatformActor) {
+	if d, ok := a.(Damageable); ok && d.Health() != nil && p.crush > 0 {
+		events.Enqueue(NewDamageEvent(a.(Actor), p, p.crush, 0))
+	}
+}
+
+// riders finds the moving actors standing on top of a collider, along with anything
+// standing on top of them, and so on.
+func (m *Manager) riders(base colliders.Collider, carried map[int]Carrier) []CanMove {
+	var out []CanMove
+	seen := map[int]bool{base.Ref(): true}
+	queue := []colliders.Collider{base}
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		bx, by, bz := b.Pos()
+		top := bz + b.ZDepth(bx, by)
+		for _, c := range m.actorColliders.GetColliding(0, 0, 1, b) {
+			mover, ok := m.actors[c.Ref()].(CanMove)
+			if !ok || seen[c.Ref()] || carried[c.Ref()] != nil || !mover.OnGround() || c.Z() < top {
+				continue
+			}
+			seen[c.Ref()] = true
+			out = append(out, mover)
+			queue = append(queue, c)
+		}
+	}
+	return out
+}
+
+// carryRiders moves riders along with whatever they stand on, unless something is in their way.
+// Returns the riders that moved, and the riders that were stuck.
+func (m *Manager) carryRiders(riders []CanMove, dx, dy, dz int, base colliders.Collider, carried map[int]Carrier) ([]CanMove, []CanMove) {
+	world := m.world(carried).ExcludeByCollider(base)
+	for _, r := range riders {
+		world = world.ExcludeByCollider(r.Collider())
+	}
+	var moved, stuck []CanMove
+	for _, r := range riders {
+		if world.WouldCollide(dx, dy, dz, r.Collider()) {
+			stuck = append(stuck, r)
+			continue
+		}
+		r.Collider().Translate(dx, dy, dz)
+		moved = append(moved, r)
+	}
+	return moved, stuck
+}
+
+// world is every blocking collider, apart from the ones being carried
+func (m *Manager) world(carried map[int]Carrier) colliders.Colliders {
+	return m.actorColliders.GetBlocking().Filter(func(c colliders.Collider, i int) bool {
+		return carried[c.Ref()] == nil
+	})
+}
@@ -15,6 +15,53 @@ func NewMoveByEvent(target Actor, dx, dy, dz float64, duration types.Frame) *eve
 	return events.New(events.Actor, MoveByActionType, []interface{}{target, dx, dy, dz, duration})
 }
 
+// NewEasedMoveByEvent creates an event that interprets as a move along an easing curve,
+// where the easing is named as in utils.EasingByName
+func NewEasedMoveByEvent(target Actor, dx, dy, dz float64, duration types.Frame, ease string) *events.Event {
+	return events.New(events.Actor, MoveByActionType, []interface{}{target, dx, dy, dz, duration, ease})
+}
+
+// NewMoveToEvent creates an event that interprets as a move to a position along an easing curve
+func NewMoveToEvent(target Actor, x, y, z float64, duration types.Frame, ease string) *events.Event {
+	return events.New(events.Actor, MoveToActionType, []interface{}{target, x, y, z, duration, ease})
+}
+
+// NewHopEvent creates an event that interprets as a hop by a delta, arcing up to the height
+func NewHopEvent(target Actor, dx, dy, dz, height float64, duration types.Frame, ease string) *events.Event {
+	return events.New(events.Actor, HopActionType, []interface{}{target, dx, dy, dz, height, duration, ease})
+}
+
+// NewOrbitEvent creates an event that interprets as an orbit around a point by some degrees
+func NewOrbitEvent(target Actor, cx, cy, degrees float64, duration types.Frame, ease string) *events.Event {
+	return events.New(events.Actor, OrbitActionType, []interface{}{target, cx, cy, degrees, duration, ease})
+}
+
+// NewChangePosEvent creates an event that interprets as an immediate change of position
+func NewChangePosEvent(target Actor, x, y, z int) *events.Event {
+	return events.New(events.Actor, ChangePosActionType, []interface{}{target, x, y, z})
+}
+
+// NewActionEvent creates an event that interprets as the provided action, e.g. an action tree
+// built out of composite actions.
+func NewActionEvent(action Action) *events.Event {
+	return events.New(events.Actor, RunActionType, []interface{}{action})
+}
+
+// NewLiftEvent creates an event that interprets as the target picking up the object
+func NewLiftEvent(target Actor, obj CanMove) *events.Event {
+	return events.New(events.Actor, LiftActionType, []interface{}{target, obj})
+}
+
+// NewThrowEvent creates an event that interprets as the target throwing what it carries
+func NewThrowEvent(target Actor) *events.Event {
+	return events.New(events.Actor, ThrowActionType, []interface{}{target})
+}
+
+// NewSwimEvent creates an event that interprets as the target swimming upwards
+func NewSwimEvent(target Actor, v float64) *events.Event {
+	return events.New(events.Actor, SwimActionType, []interface{}{target, v})
+}
+
 // NewDashEvent creates an event that interprets as an actor Dash event
 func NewDashEvent(target Actor, x, y, z float64) *events.Event {
 	return events.New(events.Actor, DashActionType, []interface{}{target, x, y, z})
@@ -25,9 +72,75 @@ func NewJumpEvent(target Actor, jump float64) *events.Event {
 	return events.New(events.Actor, JumpActionType, []interface{}{target, jump})
 }
 
+// NewCoyoteJumpEvent creates an event that interprets as a jump, even if the target is no longer on the ground.
+func NewCoyoteJumpEvent(target Actor, jump float64) *events.Event {
+	return events.New(events.Actor, JumpActionType, []interface{}{target, jump, true})
+}
+
+// NewDamageEvent creates an event that interprets as damage dealt to the target by the source.
+// The source may be nil; negative amounts heal.
+func NewDamageEvent(target, source Actor, amount int, knockback float64) *events.Event {
+	return events.New(events.Actor, DamageActionType, []interface{}{target, source, amount, knockback})
+}
+
+// NewKnockbackEvent creates an event that interprets as the target being knocked back
+func NewKnockbackEvent(target Actor, vx, vy, vz float64, duration types.Frame) *events.Event {
+	return events.New(events.Actor, KnockbackActionType, []interface{}{target, vx, vy, vz, duration})
+}
+
 // ==== Global Events
 
+// Global event codes, handled at the scene level
+const (
+	InteractEventType = iota
+	StateChangeEventType
+	DeathEventType
+	SpawnEventType
+	WarpEventType
+	LaunchEventType
+	PickupEventType
+	PushEventType
+	SceneEventTypes // scene-level global event codes are numbered from here
+)
+
 // NewInteractEvent creates an event to be interpreted at a global level
 func NewInteractEvent(subject Actor) *events.Event {
-	return events.New(0, 0, []interface{}{subject})
+	return events.New(events.Global, InteractEventType, []interface{}{subject})
+}
+
+// NewStateChangeEvent creates an event announcing a character changed states
+func NewStateChangeEvent(subject Actor, from, to CharState) *events.Event {
+	return events.New(events.Global, StateChangeEventType, []interface{}{subject, from, to})
+}
+
+// NewDeathEvent creates an event announcing an actor ran out of health
+func NewDeathEvent(subject Actor) *events.Event {
+	return events.New(events.Global, DeathEventType, []interface{}{subject})
+}
+
+// NewSpawnEvent creates an event that spawns a new actor from the named prefab at a position
+func NewSpawnEvent(prefab string, x, y, z int) *events.Event {
+	return events.New(events.Global, SpawnEventType, []interface{}{prefab, x, y, z})
+}
+
+// NewWarpEvent creates an event that takes the player to a position in another room
+func NewWarpEvent(room string, x, y, z int) *events.Event {
+	return events.New(events.Global, WarpEventType, []interface{}{room, x, y, z})
+}
+
+// NewLaunchEvent creates an event that spawns a projectile from the named prefab at a position,
+// and launches it with a velocity. The owner may be nil.
+func NewLaunchEvent(prefab string, owner Actor, x, y, z int, vx, vy, vz float64) *events.Event {
+	return events.New(events.Global, LaunchEventType, []interface{}{prefab, owner, x, y, z, vx, vy, vz})
+}
+
+// NewPickupEvent creates an event for the subject picking up some number of the named item from
+// a pickup actor, which despawns. Only the player picks things up.
+func NewPickupEvent(subject, pickup Actor, item string, count int) *events.Event {
+	return events.New(events.Global, PickupEventType, []interface{}{subject, pickup, item, count})
+}
+
+// NewPushEvent creates an event for the subject pushing a block one tile along the direction
+func NewPushEvent(subject, block Actor, dx, dy int) *events.Event {
+	return events.New(events.Global, PushEventType, []interface{}{subject, block, dx, dy})
 }
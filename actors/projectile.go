@@ -0,0 +1,186 @@
+package actors
+
+import (
+	"sort"
+
+	"enewey.com/golang-game/colliders"
+	"enewey.com/golang-game/config"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/sprites"
+	"enewey.com/golang-game/types"
+	"enewey.com/golang-game/utils"
+)
+
+// ProjectileActor flies along a velocity, either in a straight line or in a ballistic arc.
+// It expires after a lifetime or a distance. The first time it hits something it doesn't bounce
+// off, it taps its impact reactions with itself and whatever it hit, and despawns.
+// Projectiles pass through their owner and through each other.
+type ProjectileActor struct {
+	StaticActor
+	owner      Actor
+	vx, vy, vz float64
+	fx, fy, fz float64 // exact position
+	ballistic  bool    // falls under gravity
+	lifetime   types.Frame
+	distance   float64
+	age        types.Frame
+	traveled   float64
+	bounces    int // blocking colliders left to bounce off
+	damage     int
+	knockback  float64
+}
+
+// NewProjectileActor creates a projectile that expires after the lifetime in frames, or after
+// traveling the distance, whichever comes first. Zero means no limit. The collider should be
+// non-blocking, so the projectile doesn't get in the way of anything while flying.
+func NewProjectileActor(
+	category string,
+	sprite sprites.Spritemap,
+	collider colliders.Collider,
+	ox, oy int,
+	ballistic bool,
+	lifetime types.Frame,
+	distance float64,
+) *ProjectileActor {
+	x, y, z := collider.Pos()
+	return &ProjectileActor{
+		*NewStaticActor(category, sprite, collider, ox, oy),
+		nil, 0, 0, 0,
+		float64(x), float64(y), float64(z),
+		ballistic, lifetime, distance, 0, 0, 0, 0, 0,
+	}
+}
+
+// Launch sends the projectile off with a velocity. The owner (which may be nil) is never hit by it.
+func (p *ProjectileActor) Launch(owner Actor, vx, vy, vz float64) {
+	p.owner = owner
+	p.vx, p.vy, p.vz = vx, vy, vz
+}
+
+// Owner - the actor that launched the projectile, or nil
+func (p *ProjectileActor) Owner() Actor { return p.owner }
+
+// Vel - the velocity of the projectile
+func (p *ProjectileActor) Vel() (float64, float64, float64) { return p.vx, p.vy, p.vz }
+
+// SetBounces sets how many blocking colliders the projectile bounces off before it impacts
+func (p *ProjectileActor) SetBounces(n int) { p.bounces = n }
+
+// SetDamage sets the damage (and knockback) dealt to a damageable actor hit by the projectile
+func (p *ProjectileActor) SetDamage(amount int, knockback float64) {
+	p.damage, p.knockback = amount, knockback
+}
+
+// SetPos - sets the position of the projectile, which carries on flying from there
+func (p *ProjectileActor) SetPos(x, y, z int) {
+	p.collider.SetPos(x, y, z)
+	p.fx, p.fy, p.fz = float64(x), float64(y), float64(z)
+}
+
+func (p *ProjectileActor) expired() bool {
+	return (p.lifetime > 0 && p.age >= p.lifetime) || (p.distance > 0 && p.traveled >= p.distance)
+}
+
+// bounce reflects the velocity off whatever blocks the move along each axis
+func (p *ProjectileActor) bounce(dx, dy, dz int, blockers colliders.Colliders) {
+	rx := blockers.WouldCollide(dx, 0, 0, p.collider)
+	ry := blockers.WouldCollide(0, dy, 0, p.collider)
+	rz := blockers.WouldCollide(0, 0, dz, p.collider)
+	if !rx && !ry && !rz {
+		// straight into a corner
+		rx, ry, rz = true, true, true
+	}
+	if rx {
+		p.vx = -p.vx
+	}
+	if ry {
+		p.vy = -p.vy
+	}
+	if rz {
+		p.vz = -p.vz
+	}
+	p.bounces--
+}
+
+// moveProjectiles moves every projectile a frame along its flight, bouncing them off of blocking
+// colliders, and despawning the ones that hit something or expire.
+func (m *Manager) moveProjectiles(carried map[int]Carrier) {
+	ids := make([]int, 0)
+	for id, a := range m.actors {
+		if _, ok := a.(*ProjectileActor); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	gravity := config.Get().Gravity()
+	for _, id := range ids {
+		p, ok := m.actors[id].(*ProjectileActor)
+		if !ok {
+			continue
+		}
+		if p.expired() {
+			m.RemoveActor(p)
+			continue
+		}
+		p.age++
+		if p.ballistic {
+			p.vz += gravity
+		}
+
+		nx, ny, nz := p.fx+p.vx, p.fy+p.vy, p.fz+p.vz
+		x, y, z := p.Pos()
+		tx, ty, tz := round3(nx, ny, nz)
+		dx, dy, dz := tx-x, ty-y, tz-z
+
+		targets := m.targets(p, carried)
+		hits := targets.GetColliding(dx, dy, dz, p.Collider())
+		if len(hits) == 0 {
+			p.fx, p.fy, p.fz = nx, ny, nz
+			p.collider.SetPos(tx, ty, tz)
+			p.traveled += utils.Magnitude3(p.vx, p.vy, p.vz)
+			continue
+		}
+
+		if p.bounces > 0 && len(hits.GetBlocking()) == len(hits) && !m.hurtable(hits) {
+			p.bounce(dx, dy, dz, hits)
+			continue
+		}
+		m.impact(p, m.actors[hits[0].Ref()])
+	}
+}
+
+// targets are the colliders a projectile can hit: anything blocking, and anything that can be hurt
+func (m *Manager) targets(p *ProjectileActor, carried map[int]Carrier) colliders.Colliders {
+	return m.actorColliders.Filter(func(c colliders.Collider, i int) bool {
+		a := m.actors[c.Ref()]
+		if a == Actor(p) || a == p.owner || carried[c.Ref()] != nil {
+			return false
+		}
+		if _, ok := a.(*ProjectileActor); ok {
+			return false
+		}
+		return c.IsBlocking() || m.hurtable(colliders.Colliders{c})
+	})
+}
+
+// hurtable tells whether any of the colliders belong to an actor that can take damage
+func (m *Manager) hurtable(colls colliders.Colliders) bool {
+	for _, c := range colls {
+		if d, ok := m.actors[c.Ref()].(Damageable); ok && d.Health() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// impact taps the projectile's impact reactions, hurts whatever it hit, and despawns it
+func (m *Manager) impact(p *ProjectileActor, hit Actor) {
+	for _, r := range p.Collider().Reactions().OnImpact {
+		r.Tap(p, hit)
+	}
+	if d, ok := hit.(Damageable); ok && d.Health() != nil && p.damage != 0 {
+		events.Enqueue(NewDamageEvent(hit, p, p.damage, p.knockback))
+	}
+	m.RemoveActor(p)
+}
@@ -0,0 +1,8 @@
+package actors
+
+// CanPush tells whether a block could be pushed by a delta along the XY plane, without running
+// into anything blocking on the way.
+func (m *Manager) CanPush(block CanMove, dx, dy int) bool {
+	world := m.world(m.carried()).ExcludeByCollider(block.Collider())
+	return !world.WouldCollide(dx, dy, 0, block.Collider())
+}
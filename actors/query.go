@@ -0,0 +1,188 @@
+package actors
+
+import (
+	"math"
+	"sort"
+
+	"enewey.com/golang-game/utils"
+)
+
+// Query finds the actors of a manager that pass every one of its filters.
+// Results always come out in a stable order: by actor ID, or by distance and then ID.
+//
+//	m.Query().Category("guard").Within(x, y, z, 64).All()
+type Query struct {
+	m       *Manager
+	filters []func(Actor) bool
+}
+
+// Query starts a query over every actor of the manager
+func (m *Manager) Query() *Query {
+	return &Query{m, nil}
+}
+
+// Where keeps the actors that pass the test
+func (q *Query) Where(f func(Actor) bool) *Query {
+	q.filters = append(q.filters, f)
+	return q
+}
+
+// Category keeps the actors of the category
+func (q *Query) Category(c string) *Query {
+	return q.Where(func(a Actor) bool { return a.Category() == c })
+}
+
+// Name keeps the actors with the name
+func (q *Query) Name(name string) *Query {
+	return q.Where(func(a Actor) bool { return q.m.names[a.ID()] == name })
+}
+
+// Tag keeps the actors with the tag
+func (q *Query) Tag(tag string) *Query {
+	return q.Where(func(a Actor) bool { return q.m.HasTag(a, tag) })
+}
+
+// Except leaves out the actors
+func (q *Query) Except(actors ...Actor) *Query {
+	return q.Where(func(a Actor) bool {
+		for _, b := range actors {
+			if a == b {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Within keeps the actors whose center is within the radius of a point
+func (q *Query) Within(x, y, z int, radius float64) *Query {
+	return q.Where(func(a Actor) bool { return distanceTo(a, x, y, z) <= radius })
+}
+
+// InBox keeps the actors whose colliders overlap a box, including its Z range
+func (q *Query) InBox(x, y, z, w, h, d int) *Query {
+	return q.Where(func(a Actor) bool {
+		ax, ay, az := a.Pos()
+		c := a.Collider()
+		aw, ah, ad := c.XDepth(ay, az), c.YDepth(ax, az), c.ZDepth(ax, ay)
+		return ax < x+w && x < ax+aw && ay < y+h && y < ay+ah && az < z+d && z < az+ad
+	})
+}
+
+// InCone keeps the actors within the distance of the viewer, and within half the angle (in degrees)
+// either side of the direction it faces. Viewers that can't move don't face anywhere, and see nothing.
+func (q *Query) InCone(viewer Actor, degrees, dist float64) *Query {
+	mover, ok := viewer.(CanMove)
+	vx, vy, vz := viewer.Collider().Center()
+	var fx, fy float64
+	if ok {
+		fx, fy = utils.Normalize2(utils.Itof(DirToVec(mover.Direction())))
+	}
+	limit := math.Cos(degrees / 2 * math.Pi / 180)
+	return q.Where(func(a Actor) bool {
+		if !ok || a == viewer {
+			return false
+		}
+		ax, ay, _ := a.Collider().Center()
+		dx, dy := float64(ax-vx), float64(ay-vy)
+		if distanceTo(a, vx, vy, vz) > dist {
+			return false
+		}
+		if dx == 0 && dy == 0 {
+			return true
+		}
+		nx, ny := utils.Normalize2(dx, dy)
+		return nx*fx+ny*fy >= limit
+	})
+}
+
+// All lists the actors that pass the filters, by ID
+func (q *Query) All() []Actor {
+	ids := make([]int, 0, len(q.m.actors))
+	for id := range q.m.actors {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	out := []Actor{}
+next:
+	for _, id := range ids {
+		a := q.m.actors[id]
+		for _, f := range q.filters {
+			if !f(a) {
+				continue next
+			}
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// First is the actor with the lowest ID that passes the filters, or nil
+func (q *Query) First() Actor {
+	if all := q.All(); len(all) > 0 {
+		return all[0]
+	}
+	return nil
+}
+
+// ByDistance lists the actors that pass the filters, nearest to a point first
+func (q *Query) ByDistance(x, y, z int) []Actor {
+	all := q.All()
+	sort.SliceStable(all, func(i, j int) bool {
+		return distanceTo(all[i], x, y, z) < distanceTo(all[j], x, y, z)
+	})
+	return all
+}
+
+// Nearest is the actor nearest to a point that passes the filters, or nil
+func (q *Query) Nearest(x, y, z int) Actor {
+	if all := q.ByDistance(x, y, z); len(all) > 0 {
+		return all[0]
+	}
+	return nil
+}
+
+// NearestOf finds the actor of a category nearest to another actor, or nil
+func (m *Manager) NearestOf(category string, from Actor) Actor {
+	x, y, z := from.Collider().Center()
+	return m.Query().Category(category).Except(from).Nearest(x, y, z)
+}
+
+// distanceTo is the distance from the center of an actor to a point
+func distanceTo(a Actor, x, y, z int) float64 {
+	ax, ay, az := a.Collider().Center()
+	return utils.Magnitude3(float64(ax-x), float64(ay-y), float64(az-z))
+}
+
+// SetName names an actor, so it can be found by name
+func (m *Manager) SetName(a Actor, name string) {
+	m.names[a.ID()] = name
+}
+
+// Name - the name of an actor, or "" if it has none
+func (m *Manager) Name(a Actor) string {
+	return m.names[a.ID()]
+}
+
+// Tag adds tags to an actor
+func (m *Manager) Tag(a Actor, tags ...string) {
+	if m.tags[a.ID()] == nil {
+		m.tags[a.ID()] = make(map[string]bool)
+	}
+	for _, t := range tags {
+		m.tags[a.ID()][t] = true
+	}
+}
+
+// Untag removes tags from an actor
+func (m *Manager) Untag(a Actor, tags ...string) {
+	for _, t := range tags {
+		delete(m.tags[a.ID()], t)
+	}
+}
+
+// HasTag tells whether an actor has a tag
+func (m *Manager) HasTag(a Actor, tag string) bool {
+	return m.tags[a.ID()][tag]
+}
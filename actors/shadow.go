@@ -0,0 +1,83 @@
+package actors
+
+import (
+	"math"
+
+	"enewey.com/golang-game/colliders"
+	"enewey.com/golang-game/sprites"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Shadows are drawn on the floor right underneath the actors that cast them, shrinking and fading
+// the higher the actor is above the floor. Shadows have a layer of their own, drawn over the flat
+// floor actors and under every other actor, so a shadow is never drawn over an actor.
+
+// shadow sizing
+const (
+	shadowFade     = 64.0 // height above the floor at which a shadow is smallest and faintest
+	shadowMinScale = 0.4
+	shadowMaxAlpha = 0.8
+	shadowMinAlpha = 0.2
+	probeDepth     = 200000 // Z span of the probe used to find what is underneath an actor
+)
+
+// SetShadowSprite sets the sprite drawn for shadows
+func (m *Manager) SetShadowSprite(s *sprites.Sprite) { m.shadowSprite = s }
+
+// SetShadow makes an actor cast a shadow (or not)
+func (m *Manager) SetShadow(a Actor, on bool) {
+	if on {
+		m.shadowed[a.ID()] = true
+	} else {
+		delete(m.shadowed, a.ID())
+	}
+}
+
+// floorUnder finds the top of the highest blocking collider underneath the center of the subject,
+// following slopes, or false if there is nothing underneath it.
+func (m *Manager) floorUnder(subject colliders.Collider, carried map[int]Carrier) (int, bool) {
+	cx, cy, _ := subject.Center()
+	_, _, z := subject.Pos()
+	probe := colliders.NewBlock(cx, cy, -probeDepth/2, 1, 1, probeDepth, false, "shadow-probe")
+	floor, found := 0, false
+	for _, c := range m.world(carried).ExcludeByCollider(subject).GetColliding(0, 0, 0, probe) {
+		top := c.Z() + c.ZDepth(cx, cy)
+		if top <= z && (!found || top > floor) {
+			floor, found = top, true
+		}
+	}
+	return floor, found
+}
+
+// flat tells whether an actor has no height, like the floor of a room
+func flat(a Actor) bool {
+	x, y, _ := a.Collider().Pos()
+	return a.Collider().ZDepth(x, y) <= 0
+}
+
+// drawShadows draws the shadow layer, in draw order
+func (m *Manager) drawShadows(img *ebiten.Image, carried map[int]Carrier, offsetX, offsetY int) {
+	for _, a := range m.sortedActors {
+		if m.shadowed[a.ID()] && carried[a.ID()] == nil {
+			m.drawShadow(img, a, carried, offsetX, offsetY)
+		}
+	}
+}
+
+// drawShadow draws the shadow of an actor on the floor underneath it
+func (m *Manager) drawShadow(img *ebiten.Image, a Actor, carried map[int]Carrier, offsetX, offsetY int) {
+	if m.shadowSprite == nil {
+		return
+	}
+	floor, ok := m.floorUnder(a.Collider(), carried)
+	if !ok {
+		return
+	}
+	_, _, z := a.Pos()
+	height := math.Min(float64(z-floor)/shadowFade, 1)
+	scale := 1 - height*(1-shadowMinScale)
+	alpha := shadowMaxAlpha - height*(shadowMaxAlpha-shadowMinAlpha)
+
+	cx, cy, _ := a.Collider().Center()
+	m.shadowSprite.DrawFaded(cx+offsetX, cy-floor+offsetY, scale, alpha, img)
+}
@@ -0,0 +1,91 @@
+package actors
+
+import (
+	"sort"
+
+	"enewey.com/golang-game/colliders"
+	"enewey.com/golang-game/signals"
+	"enewey.com/golang-game/sprites"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// SwitchActor is a floor switch, which turns its signal on while something heavy enough is on it.
+type SwitchActor struct {
+	StaticActor
+	signal  string
+	weight  int
+	pressed bool
+}
+
+// NewSwitchActor creates a floor switch that is pressed by any moving actor with at least the
+// weight. The collider should be non-blocking, so actors can stand in it.
+func NewSwitchActor(
+	category string,
+	sprite sprites.Spritemap,
+	collider colliders.Collider,
+	ox, oy int,
+	signal string,
+	weight int,
+) *SwitchActor {
+	return &SwitchActor{*NewStaticActor(category, sprite, collider, ox, oy), signal, weight, false}
+}
+
+// Pressed tells whether something is on the switch
+func (s *SwitchActor) Pressed() bool { return s.pressed }
+
+// pressSwitches presses (or releases) every floor switch, depending on what is on it.
+// Switches are pressed in order, so whatever their signals set off happens in the same order every time.
+func (m *Manager) pressSwitches() {
+	ids := make([]int, 0)
+	for id, a := range m.actors {
+		if _, ok := a.(*SwitchActor); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		sw, ok := m.actors[id].(*SwitchActor)
+		if !ok {
+			continue
+		}
+		pressed := false
+		for _, c := range m.actorColliders.GetColliding(0, 0, 1, sw.Collider()) {
+			if mover, ok := m.actors[c.Ref()].(CanMove); ok && c != sw.Collider() && mover.Weight() >= sw.weight {
+				pressed = true
+				break
+			}
+		}
+		if pressed != sw.pressed {
+			sw.pressed = pressed
+			signals.Set(sw.signal, pressed)
+		}
+	}
+}
+
+// GateActor is a barrier that opens and closes instantly. An open gate is neither blocking nor drawn.
+type GateActor struct {
+	StaticActor
+	open bool
+}
+
+// NewGateActor creates a closed gate
+func NewGateActor(category string, sprite sprites.Spritemap, collider colliders.Collider, ox, oy int) *GateActor {
+	return &GateActor{*NewStaticActor(category, sprite, collider, ox, oy), false}
+}
+
+// Open tells whether the gate is open
+func (g *GateActor) Open() bool { return g.open }
+
+// SetOpen opens or closes the gate
+func (g *GateActor) SetOpen(open bool) {
+	g.open = open
+	g.collider.SetBlocking(!open)
+}
+
+func (g *GateActor) draw(img *ebiten.Image, offsetX, offsetY int) *ebiten.Image {
+	if g.open {
+		return img
+	}
+	return g.StaticActor.draw(img, offsetX, offsetY)
+}
@@ -0,0 +1,226 @@
+package actors
+
+import (
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/types"
+)
+
+// CharState is the current behavior of a CharActor, driven by a StateMachine.
+type CharState int
+
+// Character states
+const (
+	StateIdle CharState = iota
+	StateWalk
+	StateJump
+	StateFall
+	StateDash
+	StateLand
+	StateControlled
+	StateStunned
+	StateClimb
+	StateSwim
+)
+
+// landFrames is how long a character stays in the "land" state before going idle.
+const landFrames types.Frame = 6
+
+var stateNames = map[CharState]string{
+	StateIdle:       "idle",
+	StateWalk:       "walk",
+	StateJump:       "jump",
+	StateFall:       "fall",
+	StateDash:       "dash",
+	StateLand:       "land",
+	StateControlled: "controlled",
+	StateStunned:    "stunned",
+	StateClimb:      "climb",
+	StateSwim:       "swim",
+}
+
+func (s CharState) String() string { return stateNames[s] }
+
+// ParseCharState finds the state with the given name, e.g. "climb"
+func ParseCharState(name string) (CharState, bool) {
+	for s, n := range stateNames {
+		if n == name {
+			return s, true
+		}
+	}
+	return StateIdle, false
+}
+
+// Locked tells whether a character in this state ignores player input.
+func (s CharState) Locked() bool {
+	return s == StateControlled || s == StateStunned
+}
+
+// Airborne tells whether this is a state where the character is off the ground.
+func (s CharState) Airborne() bool {
+	return s == StateJump || s == StateFall
+}
+
+// StateCallback is invoked when a character enters or exits a state.
+type StateCallback func(a Actor, from, to CharState)
+
+// Stateful is an interface for actors driven by a StateMachine.
+type Stateful interface {
+	State() CharState
+	SetState(CharState) bool
+	StateMachine() *StateMachine
+}
+
+var _ Stateful = &CharActor{}
+
+// StateMachine tracks the state of a character, which transitions are allowed,
+// and the callbacks to invoke when entering or exiting a state.
+type StateMachine struct {
+	state, prev CharState
+	elapsed     types.Frame
+	transitions map[CharState]map[CharState]bool
+	onEnter     map[CharState][]StateCallback
+	onExit      map[CharState][]StateCallback
+}
+
+// NewStateMachine creates a state machine in the idle state, with the default transitions.
+func NewStateMachine() *StateMachine {
+	sm := &StateMachine{
+		StateIdle, StateIdle, 0,
+		make(map[CharState]map[CharState]bool),
+		make(map[CharState][]StateCallback),
+		make(map[CharState][]StateCallback),
+	}
+	sm.Allow(StateIdle, StateWalk, StateJump, StateFall, StateDash, StateControlled, StateStunned, StateClimb, StateSwim)
+	sm.Allow(StateWalk, StateIdle, StateJump, StateFall, StateDash, StateControlled, StateStunned, StateClimb, StateSwim)
+	sm.Allow(StateJump, StateFall, StateLand, StateDash, StateControlled, StateStunned, StateClimb, StateSwim)
+	sm.Allow(StateFall, StateJump, StateLand, StateDash, StateControlled, StateStunned, StateClimb, StateSwim)
+	sm.Allow(StateDash, StateIdle, StateWalk, StateFall, StateLand, StateControlled, StateStunned)
+	sm.Allow(StateLand, StateIdle, StateWalk, StateJump, StateFall, StateDash, StateControlled, StateStunned, StateClimb, StateSwim)
+	sm.Allow(StateControlled, StateIdle, StateFall)
+	sm.Allow(StateStunned, StateIdle, StateFall, StateLand, StateControlled)
+	sm.Allow(StateClimb, StateIdle, StateJump, StateFall, StateControlled, StateStunned)
+	sm.Allow(StateSwim, StateIdle, StateWalk, StateJump, StateFall, StateLand, StateControlled, StateStunned, StateClimb)
+	return sm
+}
+
+// State returns the current state
+func (sm *StateMachine) State() CharState { return sm.state }
+
+// Previous returns the state that was exited to enter the current state
+func (sm *StateMachine) Previous() CharState { return sm.prev }
+
+// Elapsed returns the number of frames spent in the current state
+func (sm *StateMachine) Elapsed() types.Frame { return sm.elapsed }
+
+// Tick increases the number of frames spent in the current state
+func (sm *StateMachine) Tick(df types.Frame) { sm.elapsed += df }
+
+// Allow adds transitions from one state to each of the provided states.
+func (sm *StateMachine) Allow(from CharState, to ...CharState) {
+	if sm.transitions[from] == nil {
+		sm.transitions[from] = make(map[CharState]bool)
+	}
+	for _, v := range to {
+		sm.transitions[from][v] = true
+	}
+}
+
+// Disallow removes transitions from one state to each of the provided states.
+func (sm *StateMachine) Disallow(from CharState, to ...CharState) {
+	for _, v := range to {
+		delete(sm.transitions[from], v)
+	}
+}
+
+// CanTransition tells whether the current state may transition to the given state.
+func (sm *StateMachine) CanTransition(to CharState) bool {
+	return sm.transitions[sm.state][to]
+}
+
+// OnEnter registers a callback for when the given state is entered.
+func (sm *StateMachine) OnEnter(s CharState, cb StateCallback) {
+	sm.onEnter[s] = append(sm.onEnter[s], cb)
+}
+
+// OnExit registers a callback for when the given state is exited.
+func (sm *StateMachine) OnExit(s CharState, cb StateCallback) {
+	sm.onExit[s] = append(sm.onExit[s], cb)
+}
+
+// transition moves the machine into a new state on behalf of the subject actor.
+// Returns false if the transition isn't allowed.
+func (sm *StateMachine) transition(subject Actor, to CharState) bool {
+	if sm.state == to || !sm.CanTransition(to) {
+		return false
+	}
+	from := sm.state
+	for _, cb := range sm.onExit[from] {
+		cb(subject, from, to)
+	}
+	sm.prev, sm.state, sm.elapsed = from, to, 0
+	for _, cb := range sm.onEnter[to] {
+		cb(subject, from, to)
+	}
+	events.Enqueue(NewStateChangeEvent(subject, from, to))
+	return true
+}
+
+// State returns the current state of the character
+func (a *CharActor) State() CharState { return a.sm.State() }
+
+// SetState attempts to transition the character to a new state.
+// Returns false if the transition is not allowed.
+func (a *CharActor) SetState(s CharState) bool { return a.sm.transition(a, s) }
+
+// StateMachine returns the character's state machine, e.g. for registering callbacks.
+func (a *CharActor) StateMachine() *StateMachine { return a.sm }
+
+// updateState resolves the physical state of the character after collisions have been resolved.
+// Controlled, stunned and dashing states are left for whatever put the character in them.
+func (a *CharActor) updateState(df types.Frame) {
+	a.sm.Tick(df)
+	switch a.State() {
+	case StateControlled, StateStunned, StateDash:
+		return
+	case StateClimb:
+		a.updateClimb()
+		return
+	}
+
+	if !a.OnGround() {
+		if med := a.Medium(); med != nil && med.Swimmable {
+			a.SetState(StateSwim)
+			return
+		}
+		if a.vz > 0 {
+			a.SetState(StateJump)
+		} else {
+			a.SetState(StateFall)
+		}
+		return
+	}
+
+	switch a.State() {
+	case StateJump, StateFall, StateSwim:
+		a.SetState(StateLand)
+		return
+	case StateLand:
+		if a.sm.Elapsed() < landFrames {
+			return
+		}
+	}
+
+	if a.vx != 0 || a.vy != 0 {
+		a.SetState(StateWalk)
+	} else {
+		a.SetState(StateIdle)
+	}
+}
+
+// groundedState is the state a character should be in when an external state is released.
+func groundedState(a CanMove) CharState {
+	if a.OnGround() {
+		return StateLand
+	}
+	return StateFall
+}
@@ -0,0 +1,75 @@
+package actors
+
+import (
+	"enewey.com/golang-game/colliders"
+	"enewey.com/golang-game/types"
+)
+
+// Moving actors inside of a volume with a medium (see colliders.Medium) are affected by the
+// medium's physics instead of the open air. Volumes react when actors enter or exit them.
+
+// swimStroke is the upwards velocity of a character swimming up
+const swimStroke = 1.5
+
+// Medium - the medium the actor is in, or nil for the open air
+func (a *MovingActor) Medium() *colliders.Medium {
+	if a.volume == nil {
+		return nil
+	}
+	return a.volume.Medium()
+}
+
+func (a *MovingActor) moving() *MovingActor { return a }
+
+// updateVolume finds the volume the subject is inside of, and taps the exit and enter reactions
+// of the volumes it leaves and enters.
+func (m *Manager) updateVolume(subject CanMove, colliderCtx colliders.Colliders) {
+	mover, ok := subject.(interface{ moving() *MovingActor })
+	if !ok {
+		return
+	}
+	a := mover.moving()
+
+	var volume colliders.Collider
+	if inside := colliderCtx.GetMedium().GetColliding(0, 0, 0, subject.Collider()); len(inside) > 0 {
+		volume = inside[0]
+	}
+	if volume == a.volume {
+		return
+	}
+	if a.volume != nil {
+		for _, r := range a.volume.Reactions().OnExit {
+			r.Tap(subject.(Actor), m.actors[a.volume.Ref()])
+		}
+	}
+	a.volume = volume
+	if volume != nil {
+		for _, r := range volume.Reactions().OnEnter {
+			r.Tap(subject.(Actor), m.actors[volume.Ref()])
+		}
+	}
+}
+
+// SwimAction makes a character swim upwards
+type SwimAction struct {
+	BaseAction
+	v float64
+}
+
+// NewSwimAction creates an action for the target to swim upwards with the velocity
+func NewSwimAction(target Actor, v float64) *SwimAction {
+	return &SwimAction{BaseAction{target, 0, 0}, v}
+}
+
+// Process w
+func (a *SwimAction) Process(df types.Frame) bool {
+	target := a.target.(CanMove)
+	if med := target.Medium(); med == nil || !med.Swimmable {
+		return true
+	}
+	if _, _, vz := target.Vel(); vz < a.v {
+		target.AddImpulse(0, 0, (a.v-vz)*target.Mass())
+	}
+	target.SetOnGround(false)
+	return true
+}
@@ -0,0 +1,189 @@
+package actors
+
+import (
+	"math"
+
+	"enewey.com/golang-game/types"
+	"enewey.com/golang-game/utils"
+)
+
+// This file contains the tween family of actions, which move an actor along a
+// curve over a number of frames. Tweens steer their target's velocity rather than
+// its position, so the target still collides with things, and whatever else pushes
+// the target around doesn't throw the tween off its curve.
+
+// exactPos gets the position of the actor including its sub-pixel offset
+func exactPos(a CanMove) (float64, float64, float64) {
+	x, y, z := a.Collider().Pos()
+	sx, sy, sz := a.SubPos()
+	return float64(x) + sx, float64(y) + sy, float64(z) + sz
+}
+
+// tween - common data for actions that progress along an easing curve.
+type tween struct {
+	BaseAction
+	ease       utils.Easing
+	started    bool
+	sx, sy, sz float64 // starting x/y/z
+}
+
+func newTween(target Actor, duration types.Frame, ease utils.Easing) tween {
+	if ease == nil {
+		ease = utils.Linear
+	}
+	if duration < 1 {
+		duration = 1
+	}
+	return tween{BaseAction{target, duration, 0}, ease, false, 0, 0, 0}
+}
+
+// step advances the tween, capturing the starting position on the first frame.
+// Returns the eased progress before and after this step, and whether the tween is done.
+func (t *tween) step(df types.Frame) (float64, float64, bool) {
+	target := t.target.(CanMove)
+	if !t.started {
+		t.sx, t.sy, t.sz = exactPos(target)
+		t.started = true
+	}
+	prev := t.ease(float64(t.elapsed) / float64(t.duration))
+	t.elapsed += df
+	if t.elapsed > t.duration {
+		return 1, 1, true
+	}
+	return prev, t.ease(float64(t.elapsed) / float64(t.duration)), false
+}
+
+// MoveToAction - moves the Actor to a specific position over a duration.
+type MoveToAction struct {
+	tween
+	tx, ty, tz float64 // target x/y/z
+}
+
+// NewMoveToAction creates an action that moves the target to a position along an easing curve.
+func NewMoveToAction(target Actor, x, y, z float64, duration types.Frame, ease utils.Easing) *MoveToAction {
+	return &MoveToAction{newTween(target, duration, ease), x, y, z}
+}
+
+// Process - processes a MoveToAction
+func (a *MoveToAction) Process(df types.Frame) bool {
+	target := a.target.(CanMove)
+	_, t, done := a.step(df)
+	if done {
+		target.Steer(0, 0, 0)
+		return true
+	}
+	// aim for where the target should be at this point of the curve,
+	// which makes up for any frames the target spent being blocked.
+	x, y, z := exactPos(target)
+	target.Steer(
+		a.sx+(a.tx-a.sx)*t-x,
+		a.sy+(a.ty-a.sy)*t-y,
+		a.sz+(a.tz-a.sz)*t-z,
+	)
+	return false
+}
+
+// MoveByAction woo
+type MoveByAction struct {
+	tween
+	dx, dy, dz float64 // delta x/y/z
+}
+
+// NewMoveByAction woo
+func NewMoveByAction(target Actor, dx, dy, dz float64, duration types.Frame) *MoveByAction {
+	return NewEasedMoveByAction(target, dx, dy, dz, duration, utils.Linear)
+}
+
+// NewEasedMoveByAction creates an action that moves the target by a delta along an easing curve.
+func NewEasedMoveByAction(target Actor, dx, dy, dz float64, duration types.Frame, ease utils.Easing) *MoveByAction {
+	return &MoveByAction{newTween(target, duration, ease), dx, dy, dz}
+}
+
+// Process w
+// When the move has no Z delta, the Z velocity is left alone so the target can still jump and fall.
+func (a *MoveByAction) Process(df types.Frame) bool {
+	target := a.target.(CanMove)
+	axes := &types.AxisMap{X: 1, Y: 1}
+	if a.dz != 0 {
+		axes.Z = 1
+	}
+	prev, t, done := a.step(df)
+	if done {
+		target.SteerAxes(axes, 0, 0, 0)
+		return true
+	}
+	target.SteerAxes(axes, a.dx*(t-prev), a.dy*(t-prev), a.dz*(t-prev))
+	return false
+}
+
+// HopAction - moves the actor by a delta while hopping along an arc of a given height.
+// The easing applies to the XY movement; the arc itself is always a parabola.
+type HopAction struct {
+	tween
+	dx, dy, dz float64
+	height     float64
+}
+
+// NewHopAction creates an action that hops the target by a delta.
+func NewHopAction(target Actor, dx, dy, dz, height float64, duration types.Frame, ease utils.Easing) *HopAction {
+	return &HopAction{newTween(target, duration, ease), dx, dy, dz, height}
+}
+
+// Process w
+func (a *HopAction) Process(df types.Frame) bool {
+	target := a.target.(CanMove)
+	prev, t, done := a.step(df)
+	if done {
+		target.Steer(0, 0, 0)
+		return true
+	}
+	linear := float64(a.elapsed) / float64(a.duration)
+	_, _, z := exactPos(target)
+	arc := a.sz + a.dz*linear + 4*a.height*linear*(1-linear)
+
+	target.SetOnGround(false)
+	target.Steer(a.dx*(t-prev), a.dy*(t-prev), arc-z)
+	target.CalcDirection()
+	return false
+}
+
+// OrbitAction - moves the actor in a circle around a center point.
+// The radius and starting angle are taken from where the actor is when the action starts.
+type OrbitAction struct {
+	tween
+	cx, cy        float64
+	sweep         float64 // radians
+	radius, angle float64
+}
+
+// NewOrbitAction creates an action that orbits the target around a point by the given degrees.
+// Positive degrees orbit clockwise (on screen).
+func NewOrbitAction(target Actor, cx, cy, degrees float64, duration types.Frame, ease utils.Easing) *OrbitAction {
+	return &OrbitAction{newTween(target, duration, ease), cx, cy, degrees * math.Pi / 180, 0, 0}
+}
+
+// Process w
+func (a *OrbitAction) Process(df types.Frame) bool {
+	target := a.target.(CanMove)
+	first := !a.started
+	_, t, done := a.step(df)
+	if first {
+		a.radius = utils.Magnitude2(a.sx-a.cx, a.sy-a.cy)
+		a.angle = math.Atan2(a.sy-a.cy, a.sx-a.cx)
+	}
+	xy := &types.AxisMap{X: 1, Y: 1}
+	if done {
+		target.SteerAxes(xy, 0, 0, 0)
+		return true
+	}
+
+	theta := a.angle + a.sweep*t
+	x, y, _ := exactPos(target)
+	target.SteerAxes(xy,
+		a.cx+a.radius*math.Cos(theta)-x,
+		a.cy+a.radius*math.Sin(theta)-y,
+		0,
+	)
+	target.CalcDirection()
+	return false
+}
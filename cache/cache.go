@@ -3,6 +3,7 @@ package cache
 import (
 	"io/ioutil"
 	"log"
+	"path"
 
 	"golang.org/x/image/font"
 
@@ -16,6 +17,7 @@ import (
 type Cache struct {
 	images map[string]*ebiten.Image
 	sheets map[string]*sprites.Spritesheet
+	asepr  map[string]*sprites.AsepriteSheet
 	fonts  map[string]font.Face
 }
 
@@ -31,6 +33,7 @@ func Get() *Cache {
 		singer = &Cache{
 			make(map[string]*ebiten.Image),
 			make(map[string]*sprites.Spritesheet),
+			make(map[string]*sprites.AsepriteSheet),
 			make(map[string]font.Face),
 		}
 	}
@@ -46,6 +49,24 @@ func (c *Cache) LoadSpritesheet(src string, th, tw int) *sprites.Spritesheet {
 	return c.sheets[src]
 }
 
+// LoadAseprite loads a sprite sheet exported from Aseprite, given the path to
+// the exported JSON. The sheet image is resolved relative to the JSON file.
+func (c *Cache) LoadAseprite(src string) *sprites.AsepriteSheet {
+	if c.asepr[src] == nil {
+		body, err := ioutil.ReadFile(imgLoc + src)
+		if err != nil {
+			log.Fatal(err)
+		}
+		data, err := sprites.ParseAseprite(body)
+		if err != nil {
+			log.Fatal(err)
+		}
+		img := c.LoadImage(path.Join(path.Dir(src), data.Meta.Image))
+		c.asepr[src] = sprites.NewAsepriteSheet(img, data)
+	}
+	return c.asepr[src]
+}
+
 // LoadImage woo
 func (c *Cache) LoadImage(src string) *ebiten.Image {
 	if c.images[src] == nil {
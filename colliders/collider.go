@@ -8,7 +8,8 @@ import (
 
 // BodyType - set of flags to describe the physical body of the collider
 type BodyType struct {
-	blocking bool
+	blocking  bool
+	climbable bool // characters touching it can climb it, like a ladder
 }
 
 // Collider - BaseZ gets the "root" Z level of the collider.
@@ -33,6 +34,13 @@ type Collider interface {
 	Ref() int
 	SetRef(int)
 	IsBlocking() bool
+	SetBlocking(bool)
+	IsClimbable() bool
+	SetClimbable(bool)
+	Medium() *Medium
+	SetMedium(*Medium)
+	Material() *Material
+	SetMaterial(*Material)
 	IsReactive(int) bool
 	Reactions() *events.ReactionHub
 }
@@ -47,6 +55,8 @@ type BaseCollider struct {
 	ref         int
 	bodyType    *BodyType
 	reactionHub *events.ReactionHub
+	medium      *Medium
+	material    *Material
 }
 
 // X returns the root x position of this Collider
@@ -90,6 +100,21 @@ func (b *BaseCollider) IsBlocking() bool {
 	return b.bodyType.blocking
 }
 
+// SetBlocking - make the collider blocking (or not)
+func (b *BaseCollider) SetBlocking(blocking bool) {
+	b.bodyType.blocking = blocking
+}
+
+// IsClimbable - tells whether characters can climb this collider
+func (b *BaseCollider) IsClimbable() bool {
+	return b.bodyType.climbable
+}
+
+// SetClimbable - make the collider climbable (or not)
+func (b *BaseCollider) SetClimbable(c bool) {
+	b.bodyType.climbable = c
+}
+
 // IsReactive - indicates the collision behavior for this collider is custom.
 func (b *BaseCollider) IsReactive(T int) bool {
 	return b.Reactions().HasReactions(T)
@@ -363,6 +388,13 @@ func (cs Colliders) GetBlocking() Colliders {
 	})
 }
 
+// GetClimbable - returns a new slice of colliders which are climbable
+func (cs Colliders) GetClimbable() Colliders {
+	return cs.Filter(func(c Collider, i int) bool {
+		return c.IsClimbable()
+	})
+}
+
 // GetReactive - returns a new slice of colliders which are reactive
 func (cs Colliders) GetReactive(T int) Colliders {
 	return cs.Filter(func(c Collider, i int) bool {
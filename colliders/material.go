@@ -0,0 +1,34 @@
+package colliders
+
+// Material describes the surface of a collider, which changes how actors standing on it move.
+type Material struct {
+	Friction         float64 // portion of its speed an actor loses each frame when it stops walking (0 to 1)
+	Accel            float64 // portion of the walking speed an actor picks up each frame (0 to 1)
+	Speed            float64 // multiplier for walking speed
+	ConveyX, ConveyY float64 // velocity of anything resting on the surface
+}
+
+// DefaultMaterial is the surface of any collider without a material; actors start and stop
+// walking immediately.
+var DefaultMaterial = &Material{1, 1, 1, 0, 0}
+
+// NewIceMaterial creates a slippery surface
+func NewIceMaterial() *Material {
+	return &Material{0.03, 0.08, 1, 0, 0}
+}
+
+// NewStickyMaterial creates a surface that is slow to walk on
+func NewStickyMaterial() *Material {
+	return &Material{1, 1, 0.5, 0, 0}
+}
+
+// NewConveyorMaterial creates a surface that moves whatever rests on it at the velocity
+func NewConveyorMaterial(vx, vy float64) *Material {
+	return &Material{1, 1, 1, vx, vy}
+}
+
+// Material - the surface material of this collider, or nil if it has none
+func (b *BaseCollider) Material() *Material { return b.material }
+
+// SetMaterial - sets the surface material of this collider
+func (b *BaseCollider) SetMaterial(m *Material) { b.material = m }
@@ -0,0 +1,39 @@
+package colliders
+
+// Medium describes the physics inside of a (usually non-blocking) volume collider, e.g. water.
+// Moving actors inside of the volume are affected by its medium instead of the open air.
+type Medium struct {
+	Gravity   float64 // multiplier for gravity
+	MaxFall   float64 // fastest an actor can fall
+	Drag      float64 // portion of an actor's velocity lost each frame
+	Buoyancy  float64 // upwards acceleration for actors light enough to float
+	MaxFloat  int     // heaviest weight that floats
+	Swimmable bool    // characters swim up instead of jumping
+	WindX     float64 // force pushing on everything inside
+	WindY     float64
+	WindZ     float64
+}
+
+// NewWaterMedium creates the medium for a typical volume of water.
+// Light actors (characters, and anything as light as them) float; heavy ones like push blocks sink.
+func NewWaterMedium() *Medium {
+	return &Medium{0.25, 1, 0.1, 0.15, 2, true, 0, 0, 0}
+}
+
+// NewWindMedium creates the medium for a gust of wind, which blows things around with the force
+func NewWindMedium(x, y, z float64) *Medium {
+	return &Medium{1, 6, 0, 0, 0, false, x, y, z}
+}
+
+// Medium - the medium inside of this collider, or nil if it has none
+func (b *BaseCollider) Medium() *Medium { return b.medium }
+
+// SetMedium - sets the medium inside of this collider
+func (b *BaseCollider) SetMedium(m *Medium) { b.medium = m }
+
+// GetMedium - returns a new slice of colliders which have a medium
+func (cs Colliders) GetMedium() Colliders {
+	return cs.Filter(func(c Collider, i int) bool {
+		return c.Medium() != nil
+	})
+}
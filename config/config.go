@@ -11,6 +11,7 @@ type Config struct {
 	TileDimX, TileDimY, TilesX, TilesY int
 	gravity                            float64
 	fontName                           string
+	movement                           Movement
 }
 
 var singer *Config
@@ -21,6 +22,7 @@ func Get() *Config {
 		singer = &Config{
 			16, 16, 15, 10, -0.25,
 			"MARKEN.TTF",
+			DefaultMovement,
 		}
 	}
 	return singer
@@ -55,6 +57,10 @@ const (
 	DownKey
 	LeftKey
 	RightKey
+	MenuKey
+	UndoKey
+	ResetKey
+	SwapKey
 )
 
 // KeyUp w
@@ -81,6 +87,18 @@ func (c *Config) KeyJump() ebiten.Key { return c.buttonSetting(JumpKey) }
 // KeyDash w
 func (c *Config) KeyDash() ebiten.Key { return c.buttonSetting(DashKey) }
 
+// KeyMenu w
+func (c *Config) KeyMenu() ebiten.Key { return c.buttonSetting(MenuKey) }
+
+// KeyUndo w
+func (c *Config) KeyUndo() ebiten.Key { return c.buttonSetting(UndoKey) }
+
+// KeyReset w
+func (c *Config) KeyReset() ebiten.Key { return c.buttonSetting(ResetKey) }
+
+// KeySwap w
+func (c *Config) KeySwap() ebiten.Key { return c.buttonSetting(SwapKey) }
+
 // ButtonSetting takes in a button function and returns what key it maps to.
 func (c *Config) buttonSetting(k int) ebiten.Key {
 	switch k {
@@ -100,6 +118,14 @@ func (c *Config) buttonSetting(k int) ebiten.Key {
 		return ebiten.KeyLeft
 	case RightKey:
 		return ebiten.KeyRight
+	case MenuKey:
+		return ebiten.KeyTab
+	case UndoKey:
+		return ebiten.KeyU
+	case ResetKey:
+		return ebiten.KeyR
+	case SwapKey:
+		return ebiten.KeyC
 	default:
 		return ebiten.KeySpace
 	}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Movement is a tunable profile of how the player moves.
+// Rates are portions (0 to 1) of the difference to the target speed made up each frame.
+type Movement struct {
+	Speed      float64 `json:"speed"`      // top walking speed
+	Accel      float64 `json:"accel"`      // rate of speeding up while walking
+	Decel      float64 `json:"decel"`      // rate of slowing down after letting go, or turning around
+	AirControl float64 `json:"airControl"` // multiplier for the rates while in the air
+	Jump       float64 `json:"jump"`       // jump velocity
+	JumpCut    float64 `json:"jumpCut"`    // upward velocity is cut down to this when jump is released early
+	Dash       float64 `json:"dash"`       // dash velocity
+	Coyote     int     `json:"coyote"`     // frames after walking off a ledge that a jump is still allowed
+	JumpBuffer int     `json:"jumpBuffer"` // frames a jump pressed too early is remembered for
+	DashBuffer int     `json:"dashBuffer"` // frames a dash pressed too early is remembered for
+}
+
+// DefaultMovement is the movement profile used until another one is loaded
+var DefaultMovement = Movement{
+	Speed:      1,
+	Accel:      0.3,
+	Decel:      0.35,
+	AirControl: 0.6,
+	Jump:       3.5,
+	JumpCut:    1.5,
+	Dash:       2.5,
+	Coyote:     6,
+	JumpBuffer: 6,
+	DashBuffer: 6,
+}
+
+// Movement - the player's movement profile
+func (c *Config) Movement() *Movement {
+	return &c.movement
+}
+
+// LoadMovement reads the player's movement profile from a json file.
+// Fields missing from the file keep their current values.
+func (c *Config) LoadMovement(source string) {
+	body, ferr := ioutil.ReadFile(source)
+	if ferr != nil {
+		fmt.Printf("error reading file")
+		panic(ferr)
+	}
+	if err := json.Unmarshal(body, &c.movement); err != nil {
+		fmt.Printf("error unmarshaling json")
+		panic(err)
+	}
+}
@@ -0,0 +1,134 @@
+package dialogue
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"enewey.com/golang-game/flags"
+)
+
+// Dialogue is a tree of nodes, where each node is a line said by a speaker. A node leads on to
+// another node, or offers choices that each lead to a node of their own. Nodes and choices can
+// depend on game flags, and nodes can set flags and signals, and run scripts, when they are reached.
+type Dialogue struct {
+	Name  string  `json:"name"`
+	Start string  `json:"start"` // the node the dialogue starts from
+	Nodes []*Node `json:"nodes"`
+}
+
+// Node is a single line of dialogue.
+// A node whose condition doesn't hold is passed over for its else node, if it has one.
+type Node struct {
+	ID      string          `json:"id"`
+	Speaker string          `json:"speaker"`
+	Text    string          `json:"text"`
+	If      string          `json:"if"` // a flag that must be set, or "!flag" for one that must not
+	Else    string          `json:"else"`
+	Set     map[string]bool `json:"set"`     // flags set when the node is reached
+	Signals map[string]bool `json:"signals"` // signals of the room set when the node is reached
+	Script  string          `json:"script"`  // a script run when the node is reached
+	Next    string          `json:"next"`
+	Choices []*Choice       `json:"choices"`
+}
+
+// Choice is an option offered to the player after a node. Choices whose condition doesn't hold
+// aren't offered.
+type Choice struct {
+	Text string `json:"text"`
+	If   string `json:"if"`
+	Next string `json:"next"`
+}
+
+var loaded = make(map[string]*Dialogue)
+
+// Load reads a dialogue file, and checks that every node it leads to exists. Dialogues are only
+// read once; later loads of the same file return the same dialogue.
+func Load(file string) (*Dialogue, error) {
+	if d, ok := loaded[file]; ok {
+		return d, nil
+	}
+	body, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var d Dialogue
+	if err := json.Unmarshal(body, &d); err != nil {
+		return nil, err
+	}
+	if err := d.check(); err != nil {
+		return nil, err
+	}
+	loaded[file] = &d
+	return &d, nil
+}
+
+// check makes sure the start node, and every node that the nodes and choices lead to, exists
+func (d *Dialogue) check() error {
+	ids := make(map[string]bool)
+	for _, n := range d.Nodes {
+		ids[n.ID] = true
+	}
+	missing := func(id string) bool { return id != "" && !ids[id] }
+
+	if !ids[d.Start] {
+		return fmt.Errorf("dialogue %s: no start node %s", d.Name, d.Start)
+	}
+	for _, n := range d.Nodes {
+		if missing(n.Next) || missing(n.Else) {
+			return fmt.Errorf("dialogue %s: node %s leads to a missing node", d.Name, n.ID)
+		}
+		for _, c := range n.Choices {
+			if missing(c.Next) {
+				return fmt.Errorf("dialogue %s: a choice of node %s leads to a missing node %s", d.Name, n.ID, c.Next)
+			}
+		}
+	}
+	return nil
+}
+
+// Node finds a node by its ID, where "" is the start node. Returns nil if there is no such node.
+func (d *Dialogue) Node(id string) *Node {
+	if id == "" {
+		id = d.Start
+	}
+	for _, n := range d.Nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+	fmt.Printf("dialogue %s: no node %s\n", d.Name, id)
+	return nil
+}
+
+// Line is the text of the node, along with who says it
+func (n *Node) Line() string {
+	if n.Speaker == "" {
+		return n.Text
+	}
+	return n.Speaker + ": " + n.Text
+}
+
+// Offered lists the choices of the node whose conditions hold
+func (n *Node) Offered() []*Choice {
+	var out []*Choice
+	for _, c := range n.Choices {
+		if Holds(c.If) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Holds tells whether a condition holds: "" always holds, "flag" holds while the flag is set,
+// and "!flag" holds while it isn't.
+func Holds(cond string) bool {
+	if cond == "" {
+		return true
+	}
+	if strings.HasPrefix(cond, "!") {
+		return !flags.Is(cond[1:])
+	}
+	return flags.Is(cond)
+}
@@ -6,16 +6,53 @@ import (
 
 // NewMessageReaction creates a reaction that produces one or more default messages windows.
 func NewMessageReaction(messages []string) Reaction {
-	cfg := config.Get()
 	ret := []*Event{}
 	for _, v := range messages {
-		ret = append(ret, NewMessageWindowEvent(0, (cfg.ScreenHeight()*2)/3,
-			cfg.ScreenWidth(), (cfg.ScreenHeight()/3)+1, v))
+		ret = append(ret, NewMessageEvent(v))
 	}
 	return NewReaction(func(...interface{}) { EnqueueAll(ret) })
 }
 
+// NewMessageEvent - event for a message window in the default spot at the bottom of the screen
+func NewMessageEvent(msg string) *Event {
+	cfg := config.Get()
+	return NewMessageWindowEvent(0, (cfg.ScreenHeight()*2)/3,
+		cfg.ScreenWidth(), (cfg.ScreenHeight()/3)+1, msg)
+}
+
 // NewMessageWindowEvent - event for a window message
 func NewMessageWindowEvent(x, y, w, h int, msg string) *Event {
 	return &Event{2, 0, []interface{}{x, y, w, h, msg}}
 }
+
+// NewInventoryEvent - event for an inventory window in the default spot on the right of the screen
+func NewInventoryEvent() *Event {
+	cfg := config.Get()
+	return NewInventoryWindowEvent(cfg.ScreenWidth()/2, 0, cfg.ScreenWidth()/2, (cfg.ScreenHeight()*2)/3)
+}
+
+// NewInventoryWindowEvent - event for a window listing the player's inventory
+func NewInventoryWindowEvent(x, y, w, h int) *Event {
+	return &Event{2, 1, []interface{}{x, y, w, h}}
+}
+
+// NewMessageThenEvent - event for a message window in the default spot, which puts another
+// event onto the bus once it is dismissed
+func NewMessageThenEvent(msg string, then *Event) *Event {
+	ev := NewMessageEvent(msg)
+	ev.payload = append(ev.payload, then)
+	return ev
+}
+
+// NewChoiceEvent - event for a choice window in the default spot at the bottom of the screen,
+// where each option replies with an event when it is picked
+func NewChoiceEvent(prompt string, options []string, replies []*Event) *Event {
+	cfg := config.Get()
+	return NewChoiceWindowEvent(0, (cfg.ScreenHeight()*2)/3,
+		cfg.ScreenWidth(), (cfg.ScreenHeight()/3)+1, prompt, options, replies)
+}
+
+// NewChoiceWindowEvent - event for a window of options
+func NewChoiceWindowEvent(x, y, w, h int, prompt string, options []string, replies []*Event) *Event {
+	return &Event{2, 2, []interface{}{x, y, w, h, prompt, options, replies}}
+}
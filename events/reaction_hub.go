@@ -5,17 +5,25 @@ package events
 type ReactionHub struct {
 	OnCollision   []Reaction
 	OnInteraction []Reaction
+	OnStateChange []Reaction
+	OnEnter       []Reaction
+	OnExit        []Reaction
+	OnImpact      []Reaction
 }
 
 // NewReactionHub creates a reaction multiplexer with blank pipelines
 func NewReactionHub() *ReactionHub {
-	return &ReactionHub{[]Reaction{}, []Reaction{}}
+	return &ReactionHub{[]Reaction{}, []Reaction{}, []Reaction{}, []Reaction{}, []Reaction{}, []Reaction{}}
 }
 
 // Condition under which a reaction should be triggered
 const (
 	ReactionOnCollision = iota
 	ReactionOnInteraction
+	ReactionOnStateChange
+	ReactionOnEnter  // an actor entered a volume
+	ReactionOnExit   // an actor left a volume
+	ReactionOnImpact // a projectile hit something
 )
 
 // Push - add a reaction to the ReactionHub based on the Condition T
@@ -27,6 +35,18 @@ func (r *ReactionHub) Push(T int, reaction Reaction) {
 	case ReactionOnInteraction:
 		r.OnInteraction = append(r.OnInteraction, reaction)
 		break
+	case ReactionOnStateChange:
+		r.OnStateChange = append(r.OnStateChange, reaction)
+		break
+	case ReactionOnEnter:
+		r.OnEnter = append(r.OnEnter, reaction)
+		break
+	case ReactionOnExit:
+		r.OnExit = append(r.OnExit, reaction)
+		break
+	case ReactionOnImpact:
+		r.OnImpact = append(r.OnImpact, reaction)
+		break
 	}
 }
 
@@ -37,6 +57,14 @@ func (r *ReactionHub) HasReactions(T int) bool {
 		return len(r.OnCollision) != 0
 	case ReactionOnInteraction:
 		return len(r.OnInteraction) != 0
+	case ReactionOnStateChange:
+		return len(r.OnStateChange) != 0
+	case ReactionOnEnter:
+		return len(r.OnEnter) != 0
+	case ReactionOnExit:
+		return len(r.OnExit) != 0
+	case ReactionOnImpact:
+		return len(r.OnImpact) != 0
 	}
 	return false
 }
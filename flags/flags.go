@@ -0,0 +1,20 @@
+package flags
+
+// Flags are named switches which record the progress of the game, e.g. "met-the-guard".
+// Scripts set them and branch on them. A flag that has never been set is false.
+var flags = make(map[string]bool)
+
+// Set sets the named flag to the value
+func Set(name string, v bool) {
+	flags[name] = v
+}
+
+// Is tells whether the named flag is set
+func Is(name string) bool {
+	return flags[name]
+}
+
+// Reset clears every flag
+func Reset() {
+	flags = make(map[string]bool)
+}
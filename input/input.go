@@ -9,6 +9,7 @@ var keys = []ebiten.Key{
 	ebiten.KeyRight, ebiten.KeyLeft, ebiten.KeyDown, ebiten.KeyUp,
 	ebiten.KeySpace, ebiten.KeyTab, ebiten.KeyShift,
 	ebiten.KeyZ, ebiten.KeyX,
+	ebiten.KeyU, ebiten.KeyR, ebiten.KeyC,
 }
 
 // Input - map of ebiten Keys to how many frames they have been held down
@@ -0,0 +1,120 @@
+package items
+
+import (
+	"fmt"
+
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/sprites"
+)
+
+// Item is the definition of a kind of item that can be held in the inventory.
+type Item struct {
+	Name       string
+	Icon       *sprites.Sprite
+	Stackable  bool                   // more than one can be held in a single slot
+	Consumable bool                   // using the item uses one up
+	Use        func() []*events.Event // the events enqueued when the item is used; nil if it can't be used
+}
+
+var defs = make(map[string]*Item)
+
+// Define makes an item available to be picked up and held, by its name
+func Define(item *Item) {
+	defs[item.Name] = item
+}
+
+// Get finds the definition of an item by its name, or nil if there isn't one
+func Get(name string) *Item {
+	return defs[name]
+}
+
+// Stack is a slot of the inventory, holding some number of an item
+type Stack struct {
+	Item  *Item
+	Count int
+}
+
+// the player's inventory; slots stay in the order the items were picked up in
+var held []*Stack
+
+// Add puts some number of the named item into the inventory.
+// Returns false if there is no such item.
+func Add(name string, n int) bool {
+	item := Get(name)
+	if item == nil {
+		fmt.Printf("no item named %s\n", name)
+		return false
+	}
+	if item.Stackable {
+		for _, s := range held {
+			if s.Item == item {
+				s.Count += n
+				return true
+			}
+		}
+		held = append(held, &Stack{item, n})
+		return true
+	}
+	for i := 0; i < n; i++ {
+		held = append(held, &Stack{item, 1})
+	}
+	return true
+}
+
+// Remove takes some number of the named item out of the inventory, emptying slots as it goes.
+// Returns false (taking nothing) if there aren't enough.
+func Remove(name string, n int) bool {
+	if Count(name) < n {
+		return false
+	}
+	for i := len(held) - 1; i >= 0 && n > 0; i-- {
+		s := held[i]
+		if s.Item.Name != name {
+			continue
+		}
+		take := n
+		if s.Count < take {
+			take = s.Count
+		}
+		s.Count -= take
+		n -= take
+		if s.Count == 0 {
+			held = append(held[:i], held[i+1:]...)
+		}
+	}
+	return true
+}
+
+// Count tells how many of the named item are in the inventory
+func Count(name string) int {
+	total := 0
+	for _, s := range held {
+		if s.Item.Name == name {
+			total += s.Count
+		}
+	}
+	return total
+}
+
+// Held lists the slots of the inventory
+func Held() []*Stack {
+	return held
+}
+
+// Use enqueues the events of the item in a slot, using one up if it is consumable.
+// Returns false if the item can't be used.
+func Use(s *Stack) bool {
+	if s.Item.Use == nil {
+		return false
+	}
+	events.EnqueueAll(s.Item.Use())
+	if s.Item.Consumable {
+		Remove(s.Item.Name, 1)
+	}
+	return true
+}
+
+// Reset empties the inventory
+func Reset() {
+	held = nil
+}
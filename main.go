@@ -14,6 +14,8 @@ import (
 	"enewey.com/golang-game/clock"
 	"enewey.com/golang-game/colliders"
 	"enewey.com/golang-game/config"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/items"
 	"enewey.com/golang-game/scene"
 	"enewey.com/golang-game/sprites"
 	"enewey.com/golang-game/types"
@@ -32,6 +34,7 @@ func init() {
 	// game initialization
 
 	cfg = config.Get()
+	cfg.LoadMovement("assets/movement.json")
 	roomImage, _ = ebiten.NewImage(cfg.ScreenWidth()*2, cfg.ScreenHeight()*2, ebiten.FilterDefault)
 
 	// begin scene initialization
@@ -46,12 +49,24 @@ func init() {
 	)
 	charBlock := colliders.NewBlock(cX, cY, cZ, 10, 10, 14, true, "chara")
 	girl = actors.NewCharActor("player", girlChar, charBlock, -4, -8, 1)
+	girl.(actors.Damageable).SetHealth(actors.NewHealth(5, 60, actors.Respawn))
 	gameScene = scene.New(girl, "assets/rooms/v2.room.json")
 
-	shadowChar := charas.GetSprite(1)
-	shadow, hook := scene.CreateShadow(girl, shadowChar)
-	gameScene.ActorM.AddActor(shadow)
-	gameScene.ActorM.AddHook(hook)
+	gameScene.ActorM.SetShadowSprite(charas.GetSprite(1))
+	gameScene.ActorM.SetShadow(girl, true)
+
+	buddy := actors.NewCharActor("buddy", sprites.NewCharaSpritemap(
+		charas.GetSprite(3),
+		charas.GetSprite(33),
+		charas.GetSprite(63),
+		charas.GetSprite(93),
+	), colliders.NewBlock(cX, cY, cZ, 10, 10, 14, true, "buddy"), -4, -8, 1)
+	gameScene.ActorM.AddActor(buddy)
+	gameScene.ActorM.SetShadow(buddy, true)
+	party := actors.NewParty(12)
+	party.AddFollower(buddy.(*actors.CharActor))
+	gameScene.ActorM.AddHook(party)
+	party.Gather()
 
 	// end scene initialization
 
@@ -87,6 +102,43 @@ func init() {
 	stairs2 := actors.NewStaticActor("stairs2", sprites.NewStaticSpritemap(stairsSprite2), stairsCollider2, 0, -32)
 	gameScene.ActorM.AddActor(stairs2)
 
+	scene.RegisterPrefab("trampoline", func(x, y, z int) actors.Actor {
+		return scene.NewTrampoline(x, y, z, sprites.NewStaticSpritemap(tiles.GetSprite(441)))
+	})
+	scene.RegisterPrefab("push-block", func(x, y, z int) actors.Actor {
+		return scene.NewPushBlock(x, y, z, "push-block", sprites.Create2by1Block(tiles.GetSprite(366), tiles.GetSprite(133)))
+	})
+	scene.RegisterPrefab("pebble", func(x, y, z int) actors.Actor {
+		pebble := actors.NewProjectileActor(
+			"projectile",
+			sprites.NewStaticSpritemap(charas.GetSprite(1)),
+			colliders.NewBlock(x, y, z, 4, 4, 4, false, "pebble"),
+			-6, -12, true, 120, 0,
+		)
+		pebble.SetBounces(1)
+		pebble.SetDamage(1, 1.5)
+		return pebble
+	})
+
+	items.Define(&items.Item{
+		Name:       "apple",
+		Icon:       tiles.GetSprite(411),
+		Stackable:  true,
+		Consumable: true,
+		Use: func() []*events.Event {
+			return []*events.Event{actors.NewDamageEvent(gameScene.ActorM.GetPlayer(), nil, -1, 0)}
+		},
+	})
+	items.Define(&items.Item{Name: "key", Icon: tiles.GetSprite(412)})
+
+	apple := scene.NewPickup(
+		"pickup",
+		sprites.NewStaticSpritemap(tiles.GetSprite(411)),
+		colliders.NewBlock(160, 120, 0, 8, 8, 8, false, "apple-pickup"),
+		-4, -8, "apple", 2,
+	)
+	gameScene.ActorM.AddActor(apple)
+
 	rock := scene.NewTrampoline(81, 150, 0, sprites.NewStaticSpritemap(tiles.GetSprite(441)))
 	gameScene.ActorM.AddActor(rock)
 
@@ -136,8 +188,9 @@ func (g *game) Update(screen *ebiten.Image) error {
 	opt.GeoM.Scale(3, 3)
 	screen.DrawImage(rm, opt)
 	if debug {
-		x, y, z := girl.Pos()
-		vx, vy, vz := girl.(actors.CanMove).Vel()
+		player := gameScene.ActorM.GetPlayer()
+		x, y, z := player.Pos()
+		vx, vy, vz := player.(actors.CanMove).Vel()
 		ebitenutil.DebugPrint(screen, fmt.Sprintf("x: %d - %f\ny: %d - %f\nz: %d - %f", x, vx, y, vy, z, vz))
 	}
 
@@ -0,0 +1,96 @@
+package nav
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Step is a single point along a path, in world coordinates.
+// Jump tells whether the actor must jump to get to this step from the last one.
+type Step struct {
+	X, Y, Z int
+	Jump    bool
+}
+
+// FindPath runs an A* search from one world position to another, returning the
+// steps to take (not including the starting cell), or nil if there is no path.
+func (g *Grid) FindPath(sx, sy, tx, ty int) []Step {
+	start, goal := g.CellAt(sx, sy), g.CellAt(tx, ty)
+	if start == nil || goal == nil || goal.Floor == NoFloor {
+		return nil
+	}
+	if start == goal {
+		return []Step{}
+	}
+
+	type visit struct {
+		from *Cell
+		jump bool
+		cost float64
+	}
+	visited := map[*Cell]*visit{start: {nil, false, 0}}
+	closed := make(map[*Cell]bool)
+	open := &nodeHeap{}
+	heap.Push(open, &node{start, g.heuristic(start, goal)})
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node).cell
+		if current == goal {
+			break
+		}
+		if closed[current] {
+			continue
+		}
+		closed[current] = true
+
+		for _, e := range g.edges(current) {
+			cost := visited[current].cost + e.cost
+			if v, ok := visited[e.to]; ok && v.cost <= cost {
+				continue
+			}
+			visited[e.to] = &visit{current, e.jump, cost}
+			heap.Push(open, &node{e.to, cost + g.heuristic(e.to, goal)})
+		}
+	}
+
+	if visited[goal] == nil {
+		return nil
+	}
+
+	// walk backwards from the goal to build the path
+	var path []Step
+	for c := goal; c != start; c = visited[c].from {
+		x, y, z := g.Center(c)
+		path = append(path, Step{x, y, z, visited[c].jump})
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// heuristic - octile distance between two cells
+func (g *Grid) heuristic(a, b *Cell) float64 {
+	dx := math.Abs(float64(a.Col - b.Col))
+	dy := math.Abs(float64(a.Row - b.Row))
+	return (dx + dy) + (1.414-2)*math.Min(dx, dy)
+}
+
+type node struct {
+	cell     *Cell
+	priority float64
+}
+
+// nodeHeap is a min-heap of nodes, ordered by priority
+type nodeHeap []*node
+
+func (h nodeHeap) Len() int            { return len(h) }
+func (h nodeHeap) Less(i, j int) bool  { return h[i].priority < h[j].priority }
+func (h nodeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x interface{}) { *h = append(*h, x.(*node)) }
+func (h *nodeHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,195 @@
+package nav
+
+import (
+	"fmt"
+
+	"enewey.com/golang-game/colliders"
+	"enewey.com/golang-game/utils"
+)
+
+// NoFloor is the floor height of a cell with nothing to stand on
+const NoFloor = -99
+
+// NoCeiling is the ceiling height of a cell with nothing overhead
+const NoCeiling = 99999
+
+// probeDepth is the Z span of the probe used to find what covers a cell
+const probeDepth = 200000
+
+// Cell is a single tile of the navigation grid
+type Cell struct {
+	Col, Row int
+	Floor    int  // height of the walkable surface of the cell
+	Ramp     bool // the surface is a slope, e.g. a Triangle collider
+	Ceiling  int  // height of the lowest thing overhanging the floor anywhere in the cell
+}
+
+// Profile describes the movement abilities of the actors using the grid
+type Profile struct {
+	MaxStep int // highest ledge that can be walked up
+	MaxRamp int // highest rise between two cells that can be walked up along a ramp
+	MaxDrop int // deepest drop that can be walked off
+	MaxJump int // highest ledge that can be jumped up
+	MaxGap  int // widest pit (in cells) that can be jumped across
+	Height  int // headroom needed to stand in a cell
+}
+
+// DefaultProfile is roughly what the player can do with a 3.5 jump
+var DefaultProfile = Profile{MaxStep: 2, MaxRamp: 16, MaxDrop: 48, MaxJump: 16, MaxGap: 1, Height: 14}
+
+// Grid is a navigation grid built from the blocking colliders of a room.
+// Each cell knows the height of the floor at its center, so paths can go up
+// ramps and ledges and across pits.
+type Grid struct {
+	cellW, cellH int
+	cols, rows   int
+	cells        []*Cell
+	profile      Profile
+}
+
+// NewGrid builds a navigation grid over a room of cols x rows cells, using the
+// blocking colliders to find the floor height of each cell.
+func NewGrid(colls colliders.Colliders, cols, rows, cellW, cellH int, profile Profile) *Grid {
+	g := &Grid{cellW, cellH, cols, rows, make([]*Cell, cols*rows), profile}
+	blocking := colls.GetBlocking()
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			cx, cy := c*cellW+cellW/2, r*cellH+cellH/2
+			probe := colliders.NewBlock(cx, cy, -probeDepth/2, 1, 1, probeDepth, false, "nav-probe")
+
+			cell := &Cell{c, r, NoFloor, false, NoCeiling}
+			for _, v := range blocking.GetColliding(0, 0, 0, probe) {
+				top := v.Z() + v.ZDepth(cx, cy)
+				if top > cell.Floor {
+					cell.Floor = top
+					_, cell.Ramp = v.(*colliders.Triangle)
+				}
+			}
+
+			// anything hanging over the floor, anywhere in the cell, is in the way
+			if cell.Floor != NoFloor {
+				area := colliders.NewBlock(c*cellW, r*cellH, -probeDepth/2, cellW, cellH, probeDepth, false, "nav-probe")
+				for _, v := range blocking.GetColliding(0, 0, 0, area) {
+					if v.Z() > cell.Floor && v.Z() < cell.Ceiling {
+						cell.Ceiling = v.Z()
+					}
+				}
+			}
+			g.cells[r*cols+c] = cell
+		}
+	}
+	fmt.Printf("built nav grid %dx%d\n", cols, rows)
+	return g
+}
+
+// Cell returns the cell at the column and row, or nil if it is out of bounds
+func (g *Grid) Cell(col, row int) *Cell {
+	if col < 0 || row < 0 || col >= g.cols || row >= g.rows {
+		return nil
+	}
+	return g.cells[row*g.cols+col]
+}
+
+// CellAt returns the cell containing the world position
+func (g *Grid) CellAt(x, y int) *Cell {
+	return g.Cell(utils.Flint(float64(x)/float64(g.cellW)), utils.Flint(float64(y)/float64(g.cellH)))
+}
+
+// Center returns the world position of the center of the cell, standing on its floor
+func (g *Grid) Center(c *Cell) (int, int, int) {
+	return c.Col*g.cellW + g.cellW/2, c.Row*g.cellH + g.cellH/2, c.Floor
+}
+
+// CellDims returns the width and height of a cell
+func (g *Grid) CellDims() (int, int) { return g.cellW, g.cellH }
+
+// edge is a traversable connection between two cells
+type edge struct {
+	to   *Cell
+	cost float64
+	jump bool
+}
+
+var neighbors = [8][2]int{
+	{0, -1}, {1, 0}, {0, 1}, {-1, 0},
+	{1, -1}, {1, 1}, {-1, 1}, {-1, -1},
+}
+
+// traversal tells whether the actor can go from one cell to an adjacent cell,
+// and whether doing so requires a jump.
+func (g *Grid) traversal(from, to *Cell) (ok bool, jump bool) {
+	if to == nil || to.Floor == NoFloor || g.cramped(to) {
+		return false, false
+	}
+	dh := to.Floor - from.Floor
+	switch {
+	case dh <= 0:
+		return -dh <= g.profile.MaxDrop, false
+	case dh <= g.profile.MaxStep:
+		return true, false
+	case (from.Ramp || to.Ramp) && dh <= g.profile.MaxRamp:
+		return true, false
+	case dh <= g.profile.MaxJump:
+		return true, true
+	}
+	return false, false
+}
+
+// isPit tells whether a cell is too low to walk into from the given cell
+func (g *Grid) isPit(from, c *Cell) bool {
+	return c != nil && (c.Floor == NoFloor || from.Floor-c.Floor > g.profile.MaxDrop)
+}
+
+// cramped tells whether a cell doesn't have the headroom to stand in
+func (g *Grid) cramped(c *Cell) bool {
+	return c.Ceiling-c.Floor < g.profile.Height
+}
+
+// edges returns all the traversable connections out of a cell
+func (g *Grid) edges(c *Cell) []edge {
+	var ret []edge
+	for i, n := range neighbors {
+		to := g.Cell(c.Col+n[0], c.Row+n[1])
+		diagonal := i >= 4
+
+		if ok, jump := g.traversal(c, to); ok {
+			// no cutting corners on diagonals
+			if diagonal {
+				a, _ := g.traversal(c, g.Cell(c.Col+n[0], c.Row))
+				b, _ := g.traversal(c, g.Cell(c.Col, c.Row+n[1]))
+				if !a || !b {
+					continue
+				}
+			}
+			cost := 1.0
+			if diagonal {
+				cost = 1.414
+			}
+			if jump {
+				cost += 2
+			}
+			ret = append(ret, edge{to, cost, jump})
+			continue
+		}
+
+		// jumping across pits, orthogonally only
+		if diagonal || !g.isPit(c, to) {
+			continue
+		}
+		for gap := 1; gap <= g.profile.MaxGap; gap++ {
+			land := g.Cell(c.Col+n[0]*(gap+1), c.Row+n[1]*(gap+1))
+			if land == nil {
+				break
+			}
+			if g.isPit(c, land) {
+				continue
+			}
+			if land.Floor-c.Floor <= g.profile.MaxStep && !g.cramped(land) {
+				ret = append(ret, edge{land, float64(gap+1) + 2, true})
+			}
+			break
+		}
+	}
+	return ret
+}
@@ -0,0 +1,224 @@
+package scene
+
+import (
+	"fmt"
+
+	"enewey.com/golang-game/actors"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/types"
+	"enewey.com/golang-game/utils"
+)
+
+// maxSkipFrames caps how far a skipped cutscene is fast-forwarded
+const maxSkipFrames = 3600
+
+// CutsceneData is unmarshaled from the cutscenes of a room json file.
+// A cutscene is made of tracks which play side by side; each track is a list of steps played in order.
+type CutsceneData struct {
+	Name      string            `json:"name"`
+	Skippable bool              `json:"skippable"`
+	Tracks    [][]*CutsceneStep `json:"tracks"`
+}
+
+// CutsceneStep is a single step of a cutscene track. Which fields are relevant depends on the kind:
+//
+//	move    - moves the actor by x/y/z over the duration, along the easing curve
+//	moveto  - moves the actor to x/y/z over the duration, along the easing curve
+//	hop     - hops the actor by x/y/z, arcing up to the height, over the duration
+//	jump    - makes the actor jump, with the height as the jump velocity
+//	message - shows the text in a message window, and waits for it to be dismissed
+//	camera  - pans the camera to center on x/y over the duration
+//	wait    - waits for the duration
+type CutsceneStep struct {
+	Kind     string  `json:"kind"`
+	Actor    string  `json:"actor"` // "player" or an actor name
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Z        float64 `json:"z"`
+	Height   float64 `json:"height"`
+	Duration int     `json:"duration"`
+	Ease     string  `json:"ease"`
+	Text     string  `json:"text"`
+}
+
+// cutscene is a cutscene that is currently playing
+type cutscene struct {
+	data     *CutsceneData
+	action   actors.Action
+	locked   []actors.Controllable
+	skipping bool
+}
+
+// Scene-level global event types
+const (
+	CutsceneEvent = actors.SceneEventTypes + iota
+	DialogueEvent
+)
+
+// NewCutsceneEvent creates an event that plays the named cutscene of the current room
+func NewCutsceneEvent(name string) *events.Event {
+	return events.New(events.Global, CutsceneEvent, []interface{}{name})
+}
+
+// PlayingCutscene tells whether a cutscene is currently playing
+func (s *Scene) PlayingCutscene() bool { return s.cutscene != nil }
+
+// PlayCutscene starts the named cutscene, locking control of the player and every
+// actor that takes part. Returns false if there is no such cutscene, or one is already playing.
+func (s *Scene) PlayCutscene(name string) bool {
+	if s.cutscene != nil {
+		return false
+	}
+	dat := s.cutscenes[name]
+	if dat == nil {
+		fmt.Printf("no cutscene named %s\n", name)
+		return false
+	}
+
+	cs := &cutscene{data: dat}
+	lock := func(a actors.Actor) {
+		if c, ok := a.(actors.Controllable); ok && !c.Controlled() {
+			c.SetControlled(true)
+			cs.locked = append(cs.locked, c)
+		}
+	}
+	lock(s.ActorM.GetPlayer())
+
+	tracks := make([]actors.Action, 0, len(dat.Tracks))
+	for _, track := range dat.Tracks {
+		steps := make([]actors.Action, 0, len(track))
+		for _, step := range track {
+			if action := s.compileStep(cs, step, lock); action != nil {
+				steps = append(steps, action)
+			}
+		}
+		tracks = append(tracks, actors.NewSequenceAction(steps...))
+	}
+
+	cs.action = actors.NewSequenceAction(
+		actors.NewParallelAction(tracks...),
+		actors.NewCallFuncAction(s.endCutscene),
+	)
+	s.cutscene = cs
+	s.ActorM.Actions().Add(cs.action)
+	return true
+}
+
+// compileStep turns a step of a cutscene track into an action.
+func (s *Scene) compileStep(cs *cutscene, step *CutsceneStep, lock func(actors.Actor)) actors.Action {
+	ease := utils.EasingByName(step.Ease)
+	duration := types.Frame(step.Duration)
+
+	var subject actors.Actor
+	switch step.Kind {
+	case "move", "moveto", "hop", "jump":
+		subject = s.Find(step.Actor)
+		if _, ok := subject.(actors.CanMove); !ok {
+			fmt.Printf("cutscene %s: actor %s can't move\n", cs.data.Name, step.Actor)
+			return nil
+		}
+		lock(subject)
+	}
+
+	switch step.Kind {
+	case "move":
+		return actors.NewEasedMoveByAction(subject, step.X, step.Y, step.Z, duration, ease)
+	case "moveto":
+		return actors.NewMoveToAction(subject, step.X, step.Y, step.Z, duration, ease)
+	case "hop":
+		return actors.NewHopAction(subject, step.X, step.Y, step.Z, step.Height, duration, ease)
+	case "jump":
+		return actors.NewJumpAction(subject, step.Height)
+	case "message":
+		// message windows take focus, which pauses actions until the window is dismissed
+		msg := events.NewMessageEvent(step.Text)
+		return actors.NewCallFuncAction(func() {
+			if !cs.skipping {
+				events.Enqueue(msg)
+			}
+		})
+	case "camera":
+		return s.newCameraPan(int(step.X), int(step.Y), duration, ease)
+	case "wait":
+		return actors.NewWaitAction(duration)
+	}
+	fmt.Printf("cutscene %s: unknown step kind %s\n", cs.data.Name, step.Kind)
+	return nil
+}
+
+// skipCutscene fast-forwards the current cutscene to its end, without showing any messages.
+func (s *Scene) skipCutscene() {
+	if s.cutscene == nil || !s.cutscene.data.Skippable {
+		return
+	}
+	s.cutscene.skipping = true
+	s.WindowM.Clear()
+	for i := 0; i < maxSkipFrames && s.cutscene != nil; i++ {
+		s.processEvents()
+		s.WindowM.Clear()
+		s.ActorM.Act(1)
+		s.ActorM.ResolveCollisions()
+	}
+	if s.cutscene != nil {
+		// gave up on fast-forwarding; just stop where things are
+		s.ActorM.Actions().Remove(s.cutscene.action)
+		s.endCutscene()
+	}
+}
+
+// endCutscene releases control of everything the current cutscene locked
+func (s *Scene) endCutscene() {
+	if s.cutscene == nil {
+		return
+	}
+	for _, c := range s.cutscene.locked {
+		c.SetControlled(false)
+	}
+	s.cutscene = nil
+}
+
+// cameraPanAction tweens the scroll offset of the scene so that the camera centers on a point.
+type cameraPanAction struct {
+	scene    *Scene
+	tx, ty   int
+	sx, sy   int
+	duration types.Frame
+	elapsed  types.Frame
+	ease     utils.Easing
+	started  bool
+}
+
+func (s *Scene) newCameraPan(x, y int, duration types.Frame, ease utils.Easing) *cameraPanAction {
+	if duration < 1 {
+		duration = 1
+	}
+	return &cameraPanAction{scene: s, tx: x, ty: y, duration: duration, ease: ease}
+}
+
+// Target - camera pans have no target actor
+func (a *cameraPanAction) Target() actors.Actor { return nil }
+
+// Elapsed w
+func (a *cameraPanAction) Elapsed() types.Frame { return a.elapsed }
+
+// Process w
+func (a *cameraPanAction) Process(df types.Frame) bool {
+	s := a.scene
+	if !a.started {
+		a.sx, a.sy = s.offsetX, s.offsetY
+		a.started = true
+	}
+	// convert the center point into a scroll offset, kept within the room
+	ex := utils.Max(0, utils.Min(a.tx-cfg.ScreenWidth()/2, s.width*cfg.TileDimX-cfg.ScreenWidth()))
+	ey := utils.Max(0, utils.Min(a.ty-cfg.ScreenHeight()/2, s.height*cfg.TileDimY-cfg.ScreenHeight()))
+
+	a.elapsed += df
+	if a.elapsed >= a.duration || (s.cutscene != nil && s.cutscene.skipping) {
+		s.offsetX, s.offsetY = ex, ey
+		return true
+	}
+	t := a.ease(float64(a.elapsed) / float64(a.duration))
+	s.offsetX = a.sx + int(float64(ex-a.sx)*t)
+	s.offsetY = a.sy + int(float64(ey-a.sy)*t)
+	return false
+}
@@ -8,25 +8,132 @@ import (
 
 // Data is unmarshaled from a room json file
 type Data struct {
-	Name   string       `json:"name"`
-	Width  int          `json:"width"`
-	Height int          `json:"height"`
-	Actors []*ActorData `json:"actors"`
+	Name      string          `json:"name"`
+	Width     int             `json:"width"`
+	Height    int             `json:"height"`
+	Actors    []*ActorData    `json:"actors"`
+	Cutscenes []*CutsceneData `json:"cutscenes"`
+	Signals   *SignalsData    `json:"signals"`
+	Puzzle    bool            `json:"puzzle"` // pushes can be undone, and the room reset
 }
 
 // ActorData false
 type ActorData struct {
-	Name     string        `json:"name"`
-	Kind     string        `json:"kind"`
-	Sprite   *spriteData   `json:"sprite"`
-	Collider *colliderData `json:"collider"`
-	OffsetX  int           `json:"offsetX"`
-	OffsetY  int           `json:"offsetY"`
+	Name       string          `json:"name"`
+	Kind       string          `json:"kind"`
+	Sprite     *spriteData     `json:"sprite"`
+	Collider   *colliderData   `json:"collider"`
+	OffsetX    int             `json:"offsetX"`
+	OffsetY    int             `json:"offsetY"`
+	Weight     int             `json:"weight"`
+	Mass       float64         `json:"mass"` // moving and char actors; defaults to 1
+	Drag       float64         `json:"drag"` // moving and char actors
+	Controller *ControllerData `json:"controller"`
+	Reactions  []*ReactionData `json:"reactions"`
+	Platform   *PlatformData   `json:"platform"`
+	Pickup     *PickupData     `json:"pickup"`
+	Signal     *SignalData     `json:"signal"`
+	Shadow     bool            `json:"shadow"` // the actor casts a shadow
+	Tags       []string        `json:"tags"`
+	// sprites for a char actor to use in particular states, keyed by state name, e.g. "climb"
+	States map[string]*spriteData `json:"states"`
+}
+
+// PlatformData describes the path of a platform actor. Waypoints are collider positions.
+type PlatformData struct {
+	Waypoints []*WaypointData `json:"waypoints"`
+	Speed     float64         `json:"speed"`
+	Loop      bool            `json:"loop"`
+	Crush     int             `json:"crush"` // damage dealt to actors crushed by the platform
+}
+
+// SignalData wires an actor up to a signal of the room.
+// "switch" actors turn the signal on while something at least as heavy as the weight is on them.
+// "door" actors rise by the lift while the signal is on, and lower again when it is off.
+// "gate" actors open while the signal is on. "platform" actors only move while the signal is on.
+// Any other actor can take a role: a "lever" flips the signal when interacted with, and
+// a "spawner" spawns the prefab at its position whenever the signal turns on.
+type SignalData struct {
+	Name   string  `json:"name"`
+	Role   string  `json:"role"`
+	Weight int     `json:"weight"`
+	Lift   int     `json:"lift"`
+	Speed  float64 `json:"speed"` // of a door; defaults to 1
+	Prefab string  `json:"prefab"`
+}
+
+// SignalsData describes the logic nodes and timers of a room
+type SignalsData struct {
+	Nodes  []*NodeData  `json:"nodes"`
+	Timers []*TimerData `json:"timers"`
+}
+
+// NodeData describes a logic node: "and", "or", "not", "toggle" or "latch"
+type NodeData struct {
+	Kind   string   `json:"kind"`
+	Inputs []string `json:"inputs"`
+	Output string   `json:"output"`
+}
+
+// TimerData describes a timer, which turns the signal on for the first frames of every period
+type TimerData struct {
+	Signal string `json:"signal"`
+	Period int    `json:"period"`
+	On     int    `json:"on"`
+}
+
+// PickupData describes the item given by a pickup actor
+type PickupData struct {
+	Item  string `json:"item"`
+	Count int    `json:"count"` // defaults to 1
+}
+
+// WaypointData false
+type WaypointData struct {
+	X    int `json:"x"`
+	Y    int `json:"y"`
+	Z    int `json:"z"`
+	Wait int `json:"wait"`
+}
+
+// ReactionData describes a reaction triggered by an actor, when it is interacted with ("interact"),
+// collided with ("collide"), or when something enters or exits its volume ("enter", "exit"). If once is set, the reaction only ever triggers one time.
+// The reaction plays a cutscene, or runs a script file from the scripts directory (as the actor).
+type ReactionData struct {
+	On       string `json:"on"`
+	Once     bool   `json:"once"`
+	Cutscene string `json:"cutscene"`
+	Script   string `json:"script"`
+	Dialogue string `json:"dialogue"` // a file in the dialogue directory
+}
+
+// ControllerData describes an AI controller for an actor.
+// Which fields are relevant depends on the kind of controller.
+type ControllerData struct {
+	Kind      string      `json:"kind"` // wander, patrol, follow, chase, flee, face, seek, script
+	Speed     float64     `json:"speed"`
+	Region    *RegionData `json:"region"`    // wander
+	Waypoints [][2]int    `json:"waypoints"` // patrol
+	Loop      bool        `json:"loop"`      // patrol
+	Wait      int         `json:"wait"`      // patrol, wander (pause), seek (repath)
+	Move      int         `json:"move"`      // wander
+	Target    string      `json:"target"`    // follow, chase, flee, face, seek; "player" or an actor name
+	Radius    float64     `json:"radius"`    // follow (near), chase (sight), flee, face
+	Script    string      `json:"script"`    // script; a file in the scripts directory
+}
+
+// RegionData false
+type RegionData struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
 }
 
 type spriteData struct {
 	*TileSpriteData
 	*ShapeSpriteData
+	*AsepriteSpriteData
 	Kind string `json:"kind"`
 }
 
@@ -38,6 +145,14 @@ type TileSpriteData struct {
 	Tiles []int  `json:"tiles"`
 }
 
+// AsepriteSpriteData describes a sprite from an Aseprite export. If a tag is provided,
+// the sprite is the animation clip for that tag; otherwise it is a single frame.
+type AsepriteSpriteData struct {
+	File  string `json:"file"`
+	Tag   string `json:"tag"`
+	Frame int    `json:"frame"`
+}
+
 // ShapeSpriteData false
 type ShapeSpriteData struct {
 	// TODO: implement a shape sprite data
@@ -46,13 +161,43 @@ type ShapeSpriteData struct {
 type colliderData struct {
 	*BlockColliderData
 	*TriangleColliderData
-	Kind     string `json:"kind"`
-	Blocking bool   `json:"blocking"`
-	X        int    `json:"x"`
-	Y        int    `json:"y"`
-	Z        int    `json:"z"`
-	D        int    `json:"d"`
-	Name     string `json:"name"`
+	Kind      string        `json:"kind"`
+	Blocking  bool          `json:"blocking"`
+	Climbable bool          `json:"climbable"`
+	Medium    *MediumData   `json:"medium"`
+	Material  *MaterialData `json:"material"`
+	X         int           `json:"x"`
+	Y         int           `json:"y"`
+	Z         int           `json:"z"`
+	D         int           `json:"d"`
+	Name      string        `json:"name"`
+}
+
+// MediumData describes the medium inside of a volume collider. The "water" kind is a typical
+// volume of water, the "wind" kind blows things around with the wind force, and the "custom"
+// kind uses the rest of the fields.
+type MediumData struct {
+	Kind      string  `json:"kind"`
+	Gravity   float64 `json:"gravity"`
+	MaxFall   float64 `json:"maxFall"`
+	Drag      float64 `json:"drag"`
+	Buoyancy  float64 `json:"buoyancy"`
+	MaxFloat  int     `json:"maxFloat"`
+	Swimmable bool    `json:"swimmable"`
+	WindX     float64 `json:"windX"`
+	WindY     float64 `json:"windY"`
+	WindZ     float64 `json:"windZ"`
+}
+
+// MaterialData describes the surface material of a collider. The kinds are "ice", "sticky",
+// "conveyor" (using conveyX and conveyY), and "custom" which uses all of the fields.
+type MaterialData struct {
+	Kind     string  `json:"kind"`
+	Friction float64 `json:"friction"`
+	Accel    float64 `json:"accel"`
+	Speed    float64 `json:"speed"`
+	ConveyX  float64 `json:"conveyX"`
+	ConveyY  float64 `json:"conveyY"`
 }
 
 // BlockColliderData false
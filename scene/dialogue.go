@@ -0,0 +1,99 @@
+package scene
+
+import (
+	"fmt"
+	"sort"
+
+	"enewey.com/golang-game/actors"
+	"enewey.com/golang-game/dialogue"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/flags"
+	"enewey.com/golang-game/signals"
+)
+
+// NewDialogueEvent creates an event that carries on the named dialogue from a node, where ""
+// is its start node. The actor is the one being spoken to, which node scripts are run as.
+func NewDialogueEvent(name, node string, self actors.Actor) *events.Event {
+	return events.New(events.Global, DialogueEvent, []interface{}{name, node, self})
+}
+
+// loadDialogue reads a dialogue file, along with the scripts of its nodes, so that a broken
+// dialogue is found when the room is loaded rather than when it's talked to.
+func loadDialogue(file string) *dialogue.Dialogue {
+	d, err := dialogue.Load(dialogueDir + file)
+	if err != nil {
+		fmt.Printf("error loading dialogue %s: %v\n", file, err)
+		panic(err)
+	}
+	for _, n := range d.Nodes {
+		if n.Script != "" {
+			loadScript(n.Script)
+		}
+	}
+	return d
+}
+
+// runDialogue reaches a node of the named dialogue: its flags and signals are set, its script is
+// run, and its line is shown. If the node offers choices, they're shown in a choice window, and the
+// chosen one carries the dialogue on; otherwise dismissing the line carries on to the next node.
+func (s *Scene) runDialogue(name, id string, self actors.Actor) {
+	d, err := dialogue.Load(dialogueDir + name)
+	if err != nil {
+		fmt.Printf("error loading dialogue %s: %v\n", name, err)
+		return
+	}
+
+	n := d.Node(id)
+	// pass over nodes whose condition doesn't hold; a dialogue can't have more hops than nodes
+	for hops := 0; n != nil && !dialogue.Holds(n.If); hops++ {
+		if n.Else == "" || hops >= len(d.Nodes) {
+			return
+		}
+		n = d.Node(n.Else)
+	}
+	if n == nil {
+		return
+	}
+
+	for flag, v := range n.Set {
+		flags.Set(flag, v)
+	}
+	// signals are set in order, so whatever they set off happens in the same order every time
+	names := make([]string, 0, len(n.Signals))
+	for name := range n.Signals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		signals.Set(name, n.Signals[name])
+	}
+	if n.Script != "" {
+		events.Enqueue(actors.NewActionEvent(loadScript(n.Script).Action(s, self)))
+	}
+
+	if offered := n.Offered(); len(offered) > 0 {
+		options := make([]string, len(offered))
+		replies := make([]*events.Event, len(offered))
+		for i, c := range offered {
+			options[i] = c.Text
+			if c.Next != "" {
+				replies[i] = NewDialogueEvent(name, c.Next, self)
+			}
+		}
+		events.Enqueue(events.NewChoiceEvent(n.Line(), options, replies))
+		return
+	}
+
+	var next *events.Event
+	if n.Next != "" {
+		next = NewDialogueEvent(name, n.Next, self)
+	}
+	if n.Text == "" {
+		// a silent node just sets flags or runs its script, and carries straight on
+		if next != nil {
+			events.Enqueue(next)
+		}
+		return
+	}
+	events.Enqueue(events.NewMessageThenEvent(n.Line(), next))
+}
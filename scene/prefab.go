@@ -8,6 +8,7 @@ import (
 	"enewey.com/golang-game/config"
 	"enewey.com/golang-game/events"
 	"enewey.com/golang-game/input"
+	"enewey.com/golang-game/items"
 	"enewey.com/golang-game/sprites"
 	"enewey.com/golang-game/utils"
 )
@@ -39,7 +40,7 @@ func NewTrampoline(x, y, z int, sprite sprites.Spritemap) actors.Actor {
 		subject := args[0].(actors.CanMove)
 		object := args[1].(actors.Actor)
 
-		vx, vy, vz := subject.Vel()
+		_, _, vz := subject.Vel()
 		_, _, sz := subject.Collider().Pos()
 		ox, oy, oz := object.Collider().Pos()
 		od := object.Collider().ZDepth(ox, oy)
@@ -52,33 +53,20 @@ func NewTrampoline(x, y, z int, sprite sprites.Spritemap) actors.Actor {
 			upward = 3.3
 		}
 
+		// the subject lands on the trampoline this frame, and bounces off it the next
 		if sz >= oz+od && vz < 0 {
-			subject.SetVel(vx, vy, 0)
-			subject.SetOnGround(false)
-			events.Enqueue(events.New(1, actors.DashActionType, []interface{}{subject, 0.0, 0.0, upward}))
+			subject.AddImpulse(0, 0, upward*subject.Mass())
 		}
 	})
 	rock.Collider().Reactions().Push(events.ReactionOnCollision, reaction)
 	return rock
 }
 
-// CreateShadow creates a shadow actor for a given drawable actor
-func CreateShadow(subject actors.Actor, shadowSprite *sprites.Sprite) (actors.Actor, actors.PostCollisionHook) {
-	x, y, z := subject.Pos()
-	w := subject.Collider().XDepth(y, z)
-	h := subject.Collider().YDepth(x, z)
-	collider := colliders.NewBlock(x, y, z, w, h, 9, false, fmt.Sprintf("%s-shadow", subject.Collider().Name()))
-	ox, oy := subject.(actors.Drawable).DrawOffset()
-	shadow := actors.NewStaticActor("shadow", sprites.NewStaticSpritemap(shadowSprite), collider, ox, oy+4)
-
-	hook := actors.NewShadowHook(shadow, subject)
-	return shadow, hook
-}
-
 // NewPushBlock - a block that can be pushed!
+// Leaning on it for a while pushes it one tile away from the pusher, if there is room.
 func NewPushBlock(x, y, z int, name string, sprite sprites.Spritemap) actors.Actor {
 	block := actors.NewMovingActor(
-		"block",
+		pushBlockCategory,
 		sprite,
 		colliders.NewBlock(x, y, z, 16, 16, 15, true, name),
 		0, -16, 10, true,
@@ -96,11 +84,7 @@ func NewPushBlock(x, y, z int, name string, sprite sprites.Spritemap) actors.Act
 				float64(x1), float64(y1), float64(z1),
 				float64(x2), float64(y2), float64(z2),
 			))
-			events.Enqueue(
-				events.New(
-					events.Actor, actors.MoveByActionType, []interface{}{object, dx * 16.0, dy * 16.0, 0.0, 32},
-				),
-			)
+			events.Enqueue(actors.NewPushEvent(subject.(actors.Actor), object.(actors.Actor), int(dx), int(dy)))
 		},
 		func(args ...interface{}) bool {
 			// subject := args[1].(actors.CanMove)
@@ -124,3 +108,74 @@ func NewPushBlock(x, y, z int, name string, sprite sprites.Spritemap) actors.Act
 	block.Collider().Reactions().Push(events.ReactionOnInteraction, interaction)
 	return block
 }
+
+// NewPickup creates an item pickup, which puts some number of the item in the inventory when the
+// player runs into it, and despawns. The collider should be non-blocking.
+func NewPickup(category string, sprite sprites.Spritemap, collider colliders.Collider, ox, oy int, item string, count int) actors.Actor {
+	pickup := actors.NewStaticActor(category, sprite, collider, ox, oy)
+	reaction := events.NewReaction(func(args ...interface{}) {
+		events.Enqueue(actors.NewPickupEvent(args[0].(actors.Actor), pickup, item, count))
+	})
+	pickup.Collider().Reactions().Push(events.ReactionOnCollision, reaction)
+	return pickup
+}
+
+// pickup puts the items from a pickup into the inventory, if the player picked it up.
+// A pickup can be run into more than once before it despawns, so it is only picked up the first time.
+func (s *Scene) pickup(subject, pickup actors.Actor, item string, count int) {
+	if subject != s.ActorM.GetPlayer() || s.ActorM.Actors()[pickup.ID()] != pickup {
+		return
+	}
+	if items.Add(item, count) {
+		s.ActorM.RemoveActor(pickup)
+	}
+}
+
+// Prefab creates a new actor at a position
+type Prefab func(x, y, z int) actors.Actor
+
+var prefabs = make(map[string]Prefab)
+
+// RegisterPrefab makes a prefab available to be spawned by name, e.g. by scripts
+func RegisterPrefab(name string, p Prefab) {
+	prefabs[name] = p
+}
+
+// Spawn creates an actor from the named prefab and adds it to the scene.
+// Spawned actors belong to the current room, and are removed along with it.
+func (s *Scene) Spawn(name string, x, y, z int) actors.Actor {
+	p, ok := prefabs[name]
+	if !ok {
+		fmt.Printf("no prefab named %s\n", name)
+		return nil
+	}
+	a := p(x, y, z)
+	s.ActorM.AddActor(a)
+	if s.room != nil {
+		// forget the ones that have despawned since, e.g. projectiles
+		kept := s.room.spawned[:0]
+		for _, v := range s.room.spawned {
+			if s.ActorM.Actors()[v.ID()] == v {
+				kept = append(kept, v)
+			}
+		}
+		s.room.spawned = append(kept, a)
+	}
+	return a
+}
+
+// Launch spawns a projectile from the named prefab, and sends it off with a velocity
+func (s *Scene) Launch(name string, owner actors.Actor, x, y, z int, vx, vy, vz float64) *actors.ProjectileActor {
+	a := s.Spawn(name, x, y, z)
+	if a == nil {
+		return nil
+	}
+	p, ok := a.(*actors.ProjectileActor)
+	if !ok {
+		fmt.Printf("prefab %s is not a projectile\n", name)
+		s.ActorM.RemoveActor(a)
+		return nil
+	}
+	p.Launch(owner, vx, vy, vz)
+	return p
+}
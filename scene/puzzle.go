@@ -0,0 +1,145 @@
+package scene
+
+import (
+	"math"
+
+	"enewey.com/golang-game/actors"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/utils"
+)
+
+// category of push block actors
+const pushBlockCategory = "block"
+
+// pushFrames is how long it takes to push a block one tile
+const pushFrames = 16
+
+// placement is where an actor was at some point
+type placement struct {
+	actor   actors.Actor
+	x, y, z int
+}
+
+// snapshot is where the blocks and the player were before a push
+type snapshot []placement
+
+// puzzle keeps a history of the pushes made in a puzzle room, so they can be undone.
+// Pushes are grid-locked everywhere, but only puzzle rooms keep a history.
+type puzzle struct {
+	history []*snapshot
+	moving  int // pushes still in progress
+}
+
+// drop forgets a push that didn't happen after all
+func (p *puzzle) drop(shot *snapshot) {
+	for i, h := range p.history {
+		if h == shot {
+			p.history = append(p.history[:i], p.history[i+1:]...)
+			return
+		}
+	}
+}
+
+// push moves a block exactly one tile along the direction, if the tile is clear, snapping it to the
+// grid. Anything under the block is left behind, so it can be pushed off of ledges. If something
+// gets in the way while the block is moving, it goes back to the tile it started from, if that's
+// still clear.
+func (s *Scene) push(subject, block actors.Actor, dx, dy int) {
+	mover, ok := block.(actors.CanMove)
+	if !ok || (dx == 0 && dy == 0) || s.ActorM.Actors()[block.ID()] != block {
+		return
+	}
+	// a block still on the move (or falling) can't be pushed again yet
+	if vx, vy, vz := mover.Vel(); vx != 0 || vy != 0 || vz != 0 || !mover.OnGround() {
+		return
+	}
+	x, y, z := block.Pos()
+	tx := snap(x, cfg.TileDimX) + dx*cfg.TileDimX
+	ty := snap(y, cfg.TileDimY) + dy*cfg.TileDimY
+	if !s.ActorM.CanPush(mover, tx-x, ty-y) {
+		return
+	}
+
+	sx, sy := snap(x, cfg.TileDimX), snap(y, cfg.TileDimY)
+	// the puzzle of the room the push started in, even if the room changes before it's done
+	pz := s.puzzle
+	var shot *snapshot
+	if pz != nil {
+		before := s.placements()
+		shot = &before
+		pz.history = append(pz.history, shot)
+		pz.moving++
+	}
+	events.Enqueue(actors.NewActionEvent(actors.NewSequenceAction(
+		actors.NewMoveToAction(block, float64(tx), float64(ty), float64(z), pushFrames, utils.Linear),
+		actors.NewCallFuncAction(func() {
+			bx, by, bz := block.Pos()
+			switch {
+			case utils.Abs(bx-tx) <= 1 && utils.Abs(by-ty) <= 1:
+				block.SetPos(tx, ty, bz)
+			case s.ActorM.CanPush(mover, sx-bx, sy-by):
+				// blocked partway; the push didn't happen
+				block.SetPos(sx, sy, bz)
+				if pz != nil {
+					pz.drop(shot)
+				}
+			default:
+				// blocked partway, and something (like the pusher) has since stepped into the tile
+				// it came from; it stays where it was stopped, until it's pushed back onto the grid
+			}
+			mover.SetSubPos(0, 0, 0)
+			if pz != nil {
+				pz.moving--
+			}
+		}),
+	)))
+}
+
+// snap rounds a coordinate to the nearest tile
+func snap(v, tile int) int {
+	return int(math.Round(float64(v)/float64(tile))) * tile
+}
+
+// placements records where every push block and the player are
+func (s *Scene) placements() snapshot {
+	var out snapshot
+	player := s.ActorM.GetPlayer()
+	for _, a := range append(s.ActorM.Query().Category(pushBlockCategory).All(), player) {
+		x, y, z := a.Pos()
+		out = append(out, placement{a, x, y, z})
+	}
+	return out
+}
+
+// restore puts the actors back where they were
+func (s *Scene) restore(shot *snapshot) {
+	for _, p := range *shot {
+		if s.ActorM.Actors()[p.actor.ID()] != p.actor {
+			continue
+		}
+		p.actor.SetPos(p.x, p.y, p.z)
+		if mover, ok := p.actor.(actors.CanMove); ok {
+			mover.SetVel(0, 0, 0)
+			mover.SetSubPos(0, 0, 0)
+		}
+	}
+}
+
+// Undo takes back the last push of a puzzle room
+func (s *Scene) Undo() {
+	if s.puzzle == nil || s.puzzle.moving > 0 || len(s.puzzle.history) == 0 {
+		return
+	}
+	last := len(s.puzzle.history) - 1
+	s.restore(s.puzzle.history[last])
+	s.puzzle.history = s.puzzle.history[:last]
+}
+
+// ResetPuzzle takes back every push of a puzzle room
+func (s *Scene) ResetPuzzle() {
+	if s.puzzle == nil || s.puzzle.moving > 0 || len(s.puzzle.history) == 0 {
+		return
+	}
+	s.restore(s.puzzle.history[0])
+	s.puzzle.history = nil
+}
@@ -7,12 +7,20 @@ import (
 	"enewey.com/golang-game/cache"
 	"enewey.com/golang-game/colliders"
 	"enewey.com/golang-game/config"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/nav"
+	"enewey.com/golang-game/script"
 	"enewey.com/golang-game/sprites"
+	"enewey.com/golang-game/types"
 )
 
 type room struct {
 	Width, Height int
 	actors        []actors.Actor
+	data          []*ActorData
+	signals       *SignalsData
+	boundaries    []actors.Actor
+	spawned       []actors.Actor // actors spawned from prefabs while the room was loaded
 }
 
 func createRoom(dat *Data) *room {
@@ -31,10 +39,245 @@ func createRoom(dat *Data) *room {
 				adat.OffsetX,
 				adat.OffsetY,
 			)
+		case "moving":
+			a = actors.NewMovingActor(
+				adat.Name,
+				sprite,
+				collider,
+				adat.OffsetX,
+				adat.OffsetY,
+				adat.Weight,
+				true,
+			)
+		case "platform":
+			var path []actors.Waypoint
+			pdat := adat.Platform
+			if pdat == nil {
+				pdat = &PlatformData{}
+			}
+			for _, wp := range pdat.Waypoints {
+				path = append(path, actors.Waypoint{X: wp.X, Y: wp.Y, Z: wp.Z, Wait: wp.Wait})
+			}
+			a = actors.NewPlatformActor(
+				adat.Name,
+				sprite,
+				collider,
+				adat.OffsetX,
+				adat.OffsetY,
+				path,
+				pdat.Speed,
+				pdat.Loop,
+				pdat.Crush,
+			)
+		case "pickup":
+			pdat := adat.Pickup
+			if pdat == nil {
+				pdat = &PickupData{}
+			}
+			count := pdat.Count
+			if count <= 0 {
+				count = 1
+			}
+			a = NewPickup(adat.Name, sprite, collider, adat.OffsetX, adat.OffsetY, pdat.Item, count)
+		case "switch":
+			sdat := adat.Signal
+			if sdat == nil {
+				sdat = &SignalData{}
+			}
+			a = actors.NewSwitchActor(adat.Name, sprite, collider, adat.OffsetX, adat.OffsetY, sdat.Name, sdat.Weight)
+		case "door":
+			sdat := adat.Signal
+			if sdat == nil {
+				sdat = &SignalData{}
+			}
+			speed := sdat.Speed
+			if speed <= 0 {
+				speed = 1
+			}
+			x, y, z := collider.Pos()
+			path := []actors.Waypoint{{X: x, Y: y, Z: z}, {X: x, Y: y, Z: z + sdat.Lift}}
+			door := actors.NewPlatformActor(adat.Name, sprite, collider, adat.OffsetX, adat.OffsetY, path, speed, false, 0)
+			door.SetActive(false)
+			a = door
+		case "block":
+			// push blocks are always a tile wide; only the position of the collider is used
+			x, y, z := collider.Pos()
+			a = NewPushBlock(x, y, z, adat.Name, sprite)
+		case "gate":
+			a = actors.NewGateActor(adat.Name, sprite, collider, adat.OffsetX, adat.OffsetY)
+		case "char":
+			a = actors.NewCharActor(
+				adat.Name,
+				sprite,
+				collider,
+				adat.OffsetX,
+				adat.OffsetY,
+				adat.Weight,
+			)
+			for name, sdat := range adat.States {
+				if st, ok := actors.ParseCharState(name); ok {
+					a.(*actors.CharActor).SetStateSpritemap(st, loadSpriteData(sdat))
+				} else {
+					fmt.Printf("unknown state %s\n", name)
+				}
+			}
+		}
+		if mover, ok := a.(interface {
+			SetMass(float64)
+			SetDrag(float64)
+		}); ok {
+			if adat.Mass > 0 {
+				mover.SetMass(adat.Mass)
+			}
+			mover.SetDrag(adat.Drag)
 		}
 		guys[i] = a
 	}
-	return &room{dat.Width, dat.Height, guys}
+	return &room{dat.Width, dat.Height, guys, dat.Actors, dat.Signals, nil, nil}
+}
+
+// navGrid builds a navigation grid from the colliders of the room's static actors and boundaries.
+func (r *room) navGrid(boundaries []actors.Actor) *nav.Grid {
+	cfg := config.Get()
+	colls := colliders.Colliders{}
+	for _, a := range append(boundaries, r.actors...) {
+		if _, ok := a.(actors.CanMove); a == nil || ok {
+			continue
+		}
+		if _, ok := a.(*actors.PlatformActor); ok {
+			continue
+		}
+		colls = append(colls, a.Collider())
+	}
+	return nav.NewGrid(colls, r.Width, r.Height, cfg.TileDimX, cfg.TileDimY, nav.DefaultProfile)
+}
+
+// attachReactions pushes the reactions described by the room data onto the colliders of the room actors.
+func (r *room) attachReactions(env script.Env) {
+	for i, adat := range r.data {
+		if r.actors[i] == nil {
+			continue
+		}
+		for _, rdat := range adat.Reactions {
+			var T int
+			switch rdat.On {
+			case "interact":
+				T = events.ReactionOnInteraction
+			case "collide":
+				T = events.ReactionOnCollision
+			case "enter":
+				T = events.ReactionOnEnter
+			case "exit":
+				T = events.ReactionOnExit
+			default:
+				fmt.Printf("unknown reaction trigger %s\n", rdat.On)
+				continue
+			}
+			r.actors[i].Collider().Reactions().Push(T, createReaction(rdat, r.actors[i], env))
+		}
+	}
+}
+
+func createReaction(dat *ReactionData, self actors.Actor, env script.Env) events.Reaction {
+	var sc *script.Script
+	if dat.Script != "" {
+		sc = loadScript(dat.Script)
+	}
+	if dat.Dialogue != "" {
+		loadDialogue(dat.Dialogue)
+	}
+	fired := false
+	return events.NewReaction(func(...interface{}) {
+		if dat.Once && fired {
+			return
+		}
+		fired = true
+		if dat.Cutscene != "" {
+			events.Enqueue(NewCutsceneEvent(dat.Cutscene))
+		}
+		if sc != nil {
+			events.Enqueue(actors.NewActionEvent(sc.Action(env, self)))
+		}
+		if dat.Dialogue != "" {
+			events.Enqueue(NewDialogueEvent(dat.Dialogue, "", self))
+		}
+	})
+}
+
+func loadScript(file string) *script.Script {
+	sc, err := script.Load(scriptDir + file)
+	if err != nil {
+		fmt.Printf("error loading script %s: %v\n", file, err)
+		panic(err)
+	}
+	return sc
+}
+
+// target is what a room actor's controller targets by name, where "player" is whoever the player is
+// when the controller is tapped.
+func (r *room) target(name string, m *actors.Manager) actors.Target {
+	if name == "" || name == "player" {
+		return m.TargetPlayer()
+	}
+	return actors.TargetActor(r.findActor(name, nil))
+}
+
+// findActor finds a room actor by name, where "player" is the player actor.
+func (r *room) findActor(name string, player actors.Actor) actors.Actor {
+	if name == "" || name == "player" {
+		return player
+	}
+	for i, adat := range r.data {
+		if adat.Name == name {
+			return r.actors[i]
+		}
+	}
+	fmt.Printf("no actor named %s in room\n", name)
+	return nil
+}
+
+// createController creates the controller for the numbered room actor, or nil if it has none.
+func (r *room) createController(i int, m *actors.Manager, grid *nav.Grid, env script.Env) actors.Controller {
+	dat := r.data[i].Controller
+	if dat == nil {
+		return nil
+	}
+
+	switch dat.Kind {
+	case "wander":
+		var region actors.Region
+		if dat.Region != nil {
+			region = actors.Region{X: dat.Region.X, Y: dat.Region.Y, W: dat.Region.W, H: dat.Region.H}
+		} else {
+			x, y, _ := r.actors[i].Pos()
+			region = actors.Region{X: x - 32, Y: y - 32, W: 64, H: 64}
+		}
+		return actors.NewWanderController(region, dat.Speed, dat.Move, dat.Wait)
+	case "patrol":
+		points := make([]types.Point, len(dat.Waypoints))
+		for j, wp := range dat.Waypoints {
+			points[j] = types.Point{X: wp[0], Y: wp[1]}
+		}
+		return actors.NewPatrolController(points, dat.Speed, dat.Wait, dat.Loop)
+	case "follow":
+		return actors.NewFollowController(r.target(dat.Target, m), dat.Speed, dat.Radius)
+	case "chase":
+		return actors.NewChaseController(r.target(dat.Target, m), dat.Speed, dat.Radius)
+	case "flee":
+		return actors.NewFleeController(r.target(dat.Target, m), dat.Speed, dat.Radius)
+	case "face":
+		return actors.NewFaceController(r.target(dat.Target, m), dat.Radius)
+	case "seek":
+		repath := dat.Wait
+		if repath <= 0 {
+			repath = 60
+		}
+		return actors.NewSeekController(grid, r.target(dat.Target, m), dat.Speed, repath)
+	case "script":
+		return script.NewController(loadScript(dat.Script), env)
+	}
+	fmt.Printf("unknown controller kind %s\n", dat.Kind)
+	return nil
 }
 
 func loadSpriteData(dat *spriteData) sprites.Spritemap {
@@ -48,6 +291,20 @@ func loadSpriteData(dat *spriteData) sprites.Spritemap {
 			tiles[i] = cache.Get().LoadSpritesheet(dat.Sheet, cfg.TileDimX, cfg.TileDimY).GetSprite(tile)
 		}
 		s = sprites.NewStaticSpritemap(sprites.NewCompoundSprite(tiles, dat.Rows, dat.Cols, cfg.TileDimX, cfg.TileDimY))
+	case "aseprite":
+		sheet := cache.Get().LoadAseprite(dat.File)
+		if dat.Tag != "" {
+			clip := sheet.Clip(dat.Tag)
+			if clip == nil {
+				panic(fmt.Sprintf("no aseprite tag %s in %s", dat.Tag, dat.File))
+			}
+			s = sprites.NewStaticClipmap(clip)
+		} else {
+			if dat.Frame < 0 || dat.Frame >= sheet.Len() {
+				panic(fmt.Sprintf("no aseprite frame %d in %s", dat.Frame, dat.File))
+			}
+			s = sprites.NewStaticSpritemap(sheet.GetSprite(dat.Frame))
+		}
 	}
 
 	return s
@@ -55,20 +312,72 @@ func loadSpriteData(dat *spriteData) sprites.Spritemap {
 
 func loadColliderData(dat *colliderData) colliders.Collider {
 	fmt.Printf("loading collider data: %+v : ", dat)
+	var c colliders.Collider
 	if dat.BlockColliderData != nil {
 
 		data := dat.BlockColliderData
 		fmt.Printf("block collider %+v\n", data)
-		return colliders.NewBlock(dat.X, dat.Y, dat.Z, data.W, data.H, dat.D, dat.Blocking, dat.Name)
-	}
-
-	if dat.TriangleColliderData != nil {
+		c = colliders.NewBlock(dat.X, dat.Y, dat.Z, data.W, data.H, dat.D, dat.Blocking, dat.Name)
+	} else if dat.TriangleColliderData != nil {
 		data := dat.TriangleColliderData
-		return colliders.NewTriangle(
+		c = colliders.NewTriangle(
 			dat.X, dat.Y, dat.Z,
 			data.Rx2, data.Ry2, data.Rx3, data.Ry3,
 			dat.D, data.Axis, dat.Blocking, dat.Name)
 	}
 
+	if c != nil && dat.Climbable {
+		c.SetClimbable(true)
+	}
+	if c != nil && dat.Medium != nil {
+		c.SetMedium(loadMediumData(dat.Medium))
+	}
+	if c != nil && dat.Material != nil {
+		c.SetMaterial(loadMaterialData(dat.Material))
+	}
+	return c
+}
+
+func loadMediumData(dat *MediumData) *colliders.Medium {
+	switch dat.Kind {
+	case "water":
+		return colliders.NewWaterMedium()
+	case "wind":
+		return colliders.NewWindMedium(dat.WindX, dat.WindY, dat.WindZ)
+	case "custom":
+		return &colliders.Medium{
+			Gravity:   dat.Gravity,
+			MaxFall:   dat.MaxFall,
+			Drag:      dat.Drag,
+			Buoyancy:  dat.Buoyancy,
+			MaxFloat:  dat.MaxFloat,
+			Swimmable: dat.Swimmable,
+			WindX:     dat.WindX,
+			WindY:     dat.WindY,
+			WindZ:     dat.WindZ,
+		}
+	}
+	fmt.Printf("unknown medium kind %s\n", dat.Kind)
+	return nil
+}
+
+func loadMaterialData(dat *MaterialData) *colliders.Material {
+	switch dat.Kind {
+	case "ice":
+		return colliders.NewIceMaterial()
+	case "sticky":
+		return colliders.NewStickyMaterial()
+	case "conveyor":
+		return colliders.NewConveyorMaterial(dat.ConveyX, dat.ConveyY)
+	case "custom":
+		return &colliders.Material{
+			Friction: dat.Friction,
+			Accel:    dat.Accel,
+			Speed:    dat.Speed,
+			ConveyX:  dat.ConveyX,
+			ConveyY:  dat.ConveyY,
+		}
+	}
+	fmt.Printf("unknown material kind %s\n", dat.Kind)
 	return nil
 }
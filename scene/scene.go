@@ -7,6 +7,8 @@ import (
 	"enewey.com/golang-game/config"
 	"enewey.com/golang-game/events"
 	"enewey.com/golang-game/input"
+	"enewey.com/golang-game/nav"
+	"enewey.com/golang-game/signals"
 	"enewey.com/golang-game/types"
 	"enewey.com/golang-game/utils"
 	"enewey.com/golang-game/windows"
@@ -20,39 +22,138 @@ import (
 type Scene struct {
 	WindowM          *windows.Manager
 	ActorM           *actors.Manager
+	Nav              *nav.Grid
 	width, height    int
 	offsetX, offsetY int // room rendering offsets
+
+	room      *room
+	cutscenes map[string]*CutsceneData
+	cutscene  *cutscene
+	puzzle    *puzzle // nil, unless the room is a puzzle room
 }
 
 var cfg *config.Config
 
+// directories that room data files refer to other files from
+const (
+	roomDir     = "assets/rooms/"
+	scriptDir   = "assets/scripts/"
+	dialogueDir = "assets/dialogue/"
+)
+
 func init() {
 	cfg = config.Get()
 }
 
 // New creates a new scene with the given player actor and data file path
 func New(player actors.Actor, dataFile string) *Scene {
-	wmgr := windows.NewManager()
-	mgr := actors.NewManager()
-	mgr.SetPlayer(player)
+	s := &Scene{windows.NewManager(), actors.NewManager(), nil, 0, 0, 0, 0, nil, nil, nil, nil}
+	s.ActorM.SetPlayer(player)
+	s.loadRoom(dataFile)
+
+	px, py, pz := player.Pos()
+	s.offsetX, s.offsetY = getScrollOffset(
+		s.width*cfg.TileDimX,
+		s.height*cfg.TileDimY,
+		0, 0,
+		px, py, pz)
+	return s
+}
 
-	room := createRoom(FromJSON(dataFile))
-	boundaries := NewBoundaries(room.Width, room.Height)
-	for _, bound := range boundaries {
-		mgr.AddActor(bound)
+// loadRoom adds the actors of a room data file to the scene, along with their controllers,
+// reactions and cutscenes
+func (s *Scene) loadRoom(dataFile string) {
+	dat := FromJSON(dataFile)
+	room := createRoom(dat)
+	room.boundaries = NewBoundaries(room.Width, room.Height)
+	for _, bound := range room.boundaries {
+		s.ActorM.AddActor(bound)
 	}
 
-	for _, actor := range room.actors {
-		mgr.AddActor(actor)
+	s.room, s.width, s.height = room, room.Width, room.Height
+	s.Nav = room.navGrid(room.boundaries)
+	for i, actor := range room.actors {
+		if ctrl := room.createController(i, s.ActorM, s.Nav, s); ctrl != nil {
+			s.ActorM.AddActorWithController(actor, ctrl)
+		} else {
+			s.ActorM.AddActor(actor)
+		}
+		if actor == nil {
+			continue
+		}
+		s.ActorM.SetName(actor, room.data[i].Name)
+		s.ActorM.Tag(actor, room.data[i].Tags...)
+		if room.data[i].Shadow {
+			s.ActorM.SetShadow(actor, true)
+		}
 	}
 
-	px, py, pz := player.Pos()
-	ox, oy := getScrollOffset(
-		room.Width*cfg.TileDimX,
-		room.Height*cfg.TileDimY,
+	room.attachReactions(s)
+	room.wireSignals()
+
+	s.puzzle = nil
+	if dat.Puzzle {
+		s.puzzle = &puzzle{}
+	}
+
+	s.cutscenes = make(map[string]*CutsceneData)
+	for _, cs := range dat.Cutscenes {
+		s.cutscenes[cs.Name] = cs
+	}
+}
+
+// unloadRoom removes the actors of the current room from the scene, along with anything spawned
+// while it was loaded. Actors that were added to the scene some other way stay put.
+func (s *Scene) unloadRoom() {
+	for _, a := range s.room.boundaries {
+		s.ActorM.RemoveActor(a)
+	}
+	for _, a := range s.room.actors {
+		if a != nil {
+			s.ActorM.RemoveActor(a)
+		}
+	}
+	for _, a := range s.room.spawned {
+		s.ActorM.RemoveActor(a)
+	}
+}
+
+// Warp takes the player to a position in another room, replacing the current room
+func (s *Scene) Warp(roomFile string, x, y, z int) {
+	if s.cutscene != nil {
+		s.ActorM.Actions().Remove(s.cutscene.action)
+		s.endCutscene()
+	}
+	s.WindowM.Clear()
+	s.unloadRoom()
+	s.loadRoom(roomDir + roomFile)
+
+	player := s.ActorM.GetPlayer()
+	player.SetPos(x, y, z)
+	if mover, ok := player.(actors.CanMove); ok {
+		mover.SetVel(0, 0, 0)
+	}
+	if d, ok := player.(actors.Damageable); ok && d.Health() != nil {
+		d.Health().SetRespawn(x, y, z)
+	}
+	s.ActorM.Warped()
+	s.offsetX, s.offsetY = getScrollOffset(
+		s.width*cfg.TileDimX,
+		s.height*cfg.TileDimY,
 		0, 0,
-		px, py, pz)
-	return &Scene{wmgr, mgr, room.Width, room.Height, ox, oy}
+		x, y, z)
+}
+
+// Find finds an actor by name, where "player" is the player
+func (s *Scene) Find(name string) actors.Actor {
+	if name == "" || name == "player" {
+		return s.ActorM.GetPlayer()
+	}
+	a := s.ActorM.Query().Name(name).First()
+	if a == nil {
+		fmt.Printf("no actor named %s\n", name)
+	}
+	return a
 }
 
 // Update - main update loop
@@ -60,6 +161,18 @@ func (s *Scene) Update(df types.Frame) {
 	// first process inputs
 	state := input.State().Tick(df)
 
+	if s.cutscene != nil && state[cfg.KeyCancel()].JustPressed() {
+		s.skipCutscene()
+	}
+
+	if s.puzzle != nil && s.cutscene == nil && !s.WindowM.HasFocus() {
+		if state[cfg.KeyUndo()].JustPressed() {
+			s.Undo()
+		} else if state[cfg.KeyReset()].JustPressed() {
+			s.ResetPuzzle()
+		}
+	}
+
 	// windows take priority over actors
 	if !s.WindowM.HandleInput(state, df) {
 		s.ActorM.HandleInput(state, df)
@@ -73,9 +186,14 @@ func (s *Scene) Update(df types.Frame) {
 		// actors only get to act if window manager doesnt declare focus
 		s.ActorM.Act(df)
 		s.ActorM.ResolveCollisions()
+		signals.Tick(df)
 	}
 
 	//At the end of it, get the player's position and adjust the scroll offset
+	// (unless a cutscene is in charge of the camera)
+	if s.cutscene != nil {
+		return
+	}
 	px, py, pz := s.ActorM.GetPlayer().Pos()
 	s.offsetX, s.offsetY = getScrollOffset(
 		s.width*cfg.TileDimX,
@@ -104,13 +222,43 @@ func (s *Scene) processEvents() {
 
 // GlobalEventTypes
 const (
-	InteractEvent = iota
+	InteractEvent    = actors.InteractEventType
+	StateChangeEvent = actors.StateChangeEventType
+	DeathEvent       = actors.DeathEventType
+	SpawnEvent       = actors.SpawnEventType
+	WarpEvent        = actors.WarpEventType
+	LaunchEvent      = actors.LaunchEventType
+	PickupEvent      = actors.PickupEventType
+	PushEvent        = actors.PushEventType
 )
 
 func (s *Scene) handleEvent(ev *events.Event) {
+	p := ev.Payload()
 	switch ev.Code() {
 	case InteractEvent:
-		s.ActorM.HandleInteraction(ev.Payload()[0].(actors.Actor))
+		s.ActorM.HandleInteraction(p[0].(actors.Actor))
+	case StateChangeEvent:
+		s.ActorM.HandleStateChange(p[0].(actors.Actor), p[1].(actors.CharState), p[2].(actors.CharState))
+	case DeathEvent:
+		s.ActorM.HandleDeath(p[0].(actors.Actor))
+	case SpawnEvent:
+		s.Spawn(p[0].(string), p[1].(int), p[2].(int), p[3].(int))
+	case WarpEvent:
+		s.Warp(p[0].(string), p[1].(int), p[2].(int), p[3].(int))
+	case LaunchEvent:
+		var owner actors.Actor
+		if p[1] != nil {
+			owner = p[1].(actors.Actor)
+		}
+		s.Launch(p[0].(string), owner, p[2].(int), p[3].(int), p[4].(int), p[5].(float64), p[6].(float64), p[7].(float64))
+	case PickupEvent:
+		s.pickup(p[0].(actors.Actor), p[1].(actors.Actor), p[2].(string), p[3].(int))
+	case PushEvent:
+		s.push(p[0].(actors.Actor), p[1].(actors.Actor), p[2].(int), p[3].(int))
+	case CutsceneEvent:
+		s.PlayCutscene(p[0].(string))
+	case DialogueEvent:
+		s.runDialogue(p[0].(string), p[1].(string), p[2].(actors.Actor))
 	default:
 	}
 }
@@ -0,0 +1,73 @@
+package scene
+
+import (
+	"fmt"
+
+	"enewey.com/golang-game/actors"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/signals"
+)
+
+// wireSignals hooks the sources and sinks of the room up to its signals, and sets up its
+// logic nodes and timers. Signals belong to a room, so any left over from the last room are reset.
+func (r *room) wireSignals() {
+	signals.Reset()
+	for i, adat := range r.data {
+		if r.actors[i] == nil || adat.Signal == nil {
+			continue
+		}
+		wireActor(r.actors[i], adat)
+	}
+	if r.signals == nil {
+		return
+	}
+	for _, n := range r.signals.Nodes {
+		signals.AddNode(n.Kind, n.Inputs, n.Output)
+	}
+	for _, t := range r.signals.Timers {
+		signals.AddTimer(t.Signal, t.Period, t.On)
+	}
+}
+
+func wireActor(a actors.Actor, adat *ActorData) {
+	name := adat.Signal.Name
+	switch act := a.(type) {
+	case *actors.SwitchActor:
+		return
+	case *actors.GateActor:
+		signals.Listen(name, act.SetOpen)
+		act.SetOpen(signals.On(name))
+		return
+	case *actors.PlatformActor:
+		if adat.Kind == "door" {
+			signals.Listen(name, func(on bool) {
+				if on {
+					act.Travel(1)
+				} else {
+					act.Travel(0)
+				}
+			})
+		} else {
+			signals.Listen(name, act.SetActive)
+			act.SetActive(signals.On(name))
+		}
+		return
+	}
+
+	switch adat.Signal.Role {
+	case "lever":
+		a.Collider().Reactions().Push(events.ReactionOnInteraction, events.NewReaction(func(...interface{}) {
+			signals.Set(name, !signals.On(name))
+		}))
+	case "spawner":
+		prefab := adat.Signal.Prefab
+		signals.Listen(name, func(on bool) {
+			if on {
+				x, y, z := a.Pos()
+				events.Enqueue(actors.NewSpawnEvent(prefab, x, y, z))
+			}
+		})
+	default:
+		fmt.Printf("unknown signal role %s\n", adat.Signal.Role)
+	}
+}
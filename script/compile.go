@@ -0,0 +1,147 @@
+package script
+
+import (
+	"fmt"
+
+	"enewey.com/golang-game/actors"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/flags"
+	"enewey.com/golang-game/types"
+	"enewey.com/golang-game/utils"
+)
+
+// defaultJump is the jump velocity used when a script doesn't give one
+const defaultJump = 3.5
+
+// Env is the scene a script runs in
+type Env interface {
+	Find(name string) actors.Actor // finds an actor by name, where "player" is the player
+}
+
+// Action turns the script into an action tree, run by the self actor.
+// Actions hold their own progress, so a new one is needed every time the script runs.
+func (s *Script) Action(env Env, self actors.Actor) actors.Action {
+	c := &compiler{s, env, self}
+	return c.block(s.nodes)
+}
+
+type compiler struct {
+	script *Script
+	env    Env
+	self   actors.Actor
+}
+
+func (c *compiler) block(nodes []node) actors.Action {
+	children := make([]actors.Action, 0, len(nodes))
+	for _, n := range nodes {
+		if action := n.compile(c); action != nil {
+			children = append(children, action)
+		}
+	}
+	return actors.NewSequenceAction(children...)
+}
+
+// mover finds an actor that can move, or nil (after complaining) if there isn't one
+func (c *compiler) mover(who string) actors.Actor {
+	a := c.self
+	if who != "self" {
+		a = c.env.Find(who)
+	}
+	if _, ok := a.(actors.CanMove); !ok {
+		fmt.Printf("script %s: actor %s can't move\n", c.script.name, who)
+		return nil
+	}
+	return a
+}
+
+// enqueue creates an action that queues up an event
+func enqueue(ev *events.Event) actors.Action {
+	return actors.NewCallFuncAction(func() { events.Enqueue(ev) })
+}
+
+type node interface {
+	compile(c *compiler) actors.Action
+}
+
+type sayNode struct {
+	text string
+}
+
+// message windows take focus, which pauses actions until the window is dismissed
+func (n *sayNode) compile(c *compiler) actors.Action {
+	return enqueue(events.NewMessageEvent(n.text))
+}
+
+type moveNode struct {
+	who        string
+	dx, dy, dz float64
+	frames     int
+	ease       string
+}
+
+func (n *moveNode) compile(c *compiler) actors.Action {
+	a := c.mover(n.who)
+	if a == nil {
+		return nil
+	}
+	return actors.NewEasedMoveByAction(a, n.dx, n.dy, n.dz, types.Frame(n.frames), utils.EasingByName(n.ease))
+}
+
+type jumpNode struct {
+	who string
+	v   float64
+}
+
+func (n *jumpNode) compile(c *compiler) actors.Action {
+	a := c.mover(n.who)
+	if a == nil {
+		return nil
+	}
+	return actors.NewJumpAction(a, n.v)
+}
+
+type waitNode struct {
+	frames int
+}
+
+func (n *waitNode) compile(c *compiler) actors.Action {
+	return actors.NewWaitAction(types.Frame(n.frames))
+}
+
+type setNode struct {
+	flag  string
+	value bool
+}
+
+func (n *setNode) compile(c *compiler) actors.Action {
+	return actors.NewCallFuncAction(func() { flags.Set(n.flag, n.value) })
+}
+
+type ifNode struct {
+	flag      string
+	negate    bool
+	then, els []node
+}
+
+// the flag is checked when the if is reached, not when the script is compiled
+func (n *ifNode) compile(c *compiler) actors.Action {
+	return actors.NewIfAction(
+		func() bool { return flags.Is(n.flag) != n.negate },
+		c.block(n.then),
+		c.block(n.els),
+	)
+}
+
+// placeNode is a spawn or a warp
+type placeNode struct {
+	warp    bool
+	name    string
+	x, y, z int
+}
+
+func (n *placeNode) compile(c *compiler) actors.Action {
+	if n.warp {
+		return enqueue(actors.NewWarpEvent(n.name, n.x, n.y, n.z))
+	}
+	return enqueue(actors.NewSpawnEvent(n.name, n.x, n.y, n.z))
+}
@@ -0,0 +1,43 @@
+package script
+
+import (
+	"enewey.com/golang-game/actors"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/input"
+	"enewey.com/golang-game/types"
+)
+
+// Controller runs a script over and over as an actor's behavior.
+// A new run starts on the frame after the last run finishes.
+type Controller struct {
+	script  *Script
+	env     Env
+	target  int
+	running bool
+}
+
+// NewController creates a controller that runs the script in the environment.
+func NewController(script *Script, env Env) *Controller {
+	return &Controller{script, env, -1, false}
+}
+
+// SetTarget w
+func (c *Controller) SetTarget(t int) {
+	c.target = t
+}
+
+// Tap w
+func (c *Controller) Tap(target actors.Actor, state input.Input, df types.Frame) bool {
+	if c.running {
+		return true
+	}
+	if stater, ok := target.(actors.Stateful); ok && stater.State().Locked() {
+		return false
+	}
+	c.running = true
+	events.Enqueue(actors.NewActionEvent(actors.NewSequenceAction(
+		c.script.Action(c.env, target),
+		actors.NewCallFuncAction(func() { c.running = false }),
+	)))
+	return true
+}
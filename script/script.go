@@ -0,0 +1,275 @@
+package script
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// A script is a plain text file of commands, one per line, run in order.
+// Blank lines and lines starting with # are ignored. Arguments are separated by spaces;
+// use double quotes for an argument with spaces in it.
+//
+//	say "text"                            - shows a message, and waits for it to be dismissed
+//	move <who> <dx> <dy> <dz> <frames> [ease] - moves an actor by a delta, see utils.EasingByName
+//	jump <who> [velocity]                 - makes an actor jump
+//	wait <frames>                         - does nothing for a while
+//	set <flag> [true|false]               - sets (or clears) a flag
+//	if <flag> / if !<flag>                - runs the following lines if the flag is set (or not),
+//	else                                    up until the matching else or end
+//	end
+//	spawn <prefab> <x> <y> <z>            - spawns an actor from a prefab
+//	warp <room> <x> <y> <z>               - takes the player to another room
+//
+// <who> is "self" for the actor running the script, "player", or the name of an actor in the room.
+//
+// e.g.
+//
+//	if met-guard
+//		say "Move along."
+//	else
+//		say "Halt! Who goes there?"
+//		jump self
+//		set met-guard
+//	end
+
+// Script is a parsed script, ready to be turned into actions.
+type Script struct {
+	name  string
+	nodes []node
+}
+
+// Name is the name the script was parsed with, e.g. its file name
+func (s *Script) Name() string { return s.name }
+
+var loaded = make(map[string]*Script)
+
+// Load reads and parses a script file. Scripts are only parsed once; later loads of the same file
+// return the same script.
+func Load(file string) (*Script, error) {
+	if s, ok := loaded[file]; ok {
+		return s, nil
+	}
+	body, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Parse(file, string(body))
+	if err != nil {
+		return nil, err
+	}
+	loaded[file] = s
+	return s, nil
+}
+
+// Parse parses the source of a script. The name is used in error messages.
+func Parse(name, src string) (*Script, error) {
+	p := &parser{name: name, lines: strings.Split(src, "\n")}
+	nodes, term, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	if term != "" {
+		return nil, p.errorf("%s without if", term)
+	}
+	return &Script{name, nodes}, nil
+}
+
+type parser struct {
+	name  string
+	lines []string
+	line  int // the line currently being parsed, counting from 1
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s", p.name, p.line, fmt.Sprintf(format, args...))
+}
+
+// block parses lines until the end of the script, or an "else" or "end".
+// Returns the nodes, and which of "else" or "end" stopped it (or "" at the end of the script).
+func (p *parser) block() ([]node, string, error) {
+	var nodes []node
+	for p.line < len(p.lines) {
+		p.line++
+		args, err := tokenize(p.lines[p.line-1])
+		if err != nil {
+			return nil, "", p.errorf("%s", err)
+		}
+		if len(args) == 0 || strings.HasPrefix(args[0], "#") {
+			continue
+		}
+
+		cmd, args := args[0], args[1:]
+		var n node
+		switch cmd {
+		case "else", "end":
+			if len(args) != 0 {
+				return nil, "", p.errorf("%s takes no arguments", cmd)
+			}
+			return nodes, cmd, nil
+		case "if":
+			n, err = p.ifNode(args)
+		case "say":
+			if len(args) == 0 {
+				return nil, "", p.errorf("say needs some text")
+			}
+			n = &sayNode{strings.Join(args, " ")}
+		case "move":
+			n, err = p.moveNode(args)
+		case "jump":
+			n, err = p.jumpNode(args)
+		case "wait":
+			n, err = p.waitNode(args)
+		case "set":
+			n, err = p.setNode(args)
+		case "spawn", "warp":
+			n, err = p.placeNode(cmd, args)
+		default:
+			err = p.errorf("unknown command %s", cmd)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, "", nil
+}
+
+func (p *parser) ifNode(args []string) (node, error) {
+	if len(args) != 1 {
+		return nil, p.errorf("if takes one flag")
+	}
+	start := p.line
+	n := &ifNode{flag: strings.TrimPrefix(args[0], "!"), negate: strings.HasPrefix(args[0], "!")}
+
+	var term string
+	var err error
+	if n.then, term, err = p.block(); err != nil {
+		return nil, err
+	}
+	if term == "else" {
+		if n.els, term, err = p.block(); err != nil {
+			return nil, err
+		}
+	}
+	if term != "end" {
+		p.line = start
+		return nil, p.errorf("if without end")
+	}
+	return n, nil
+}
+
+func (p *parser) moveNode(args []string) (node, error) {
+	if len(args) != 5 && len(args) != 6 {
+		return nil, p.errorf("move takes an actor, a delta, a number of frames, and maybe an easing")
+	}
+	nums, err := p.floats(args[1:5])
+	if err != nil {
+		return nil, err
+	}
+	n := &moveNode{args[0], nums[0], nums[1], nums[2], int(nums[3]), ""}
+	if len(args) == 6 {
+		n.ease = args[5]
+	}
+	return n, nil
+}
+
+func (p *parser) jumpNode(args []string) (node, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, p.errorf("jump takes an actor, and maybe a velocity")
+	}
+	n := &jumpNode{args[0], defaultJump}
+	if len(args) == 2 {
+		nums, err := p.floats(args[1:])
+		if err != nil {
+			return nil, err
+		}
+		n.v = nums[0]
+	}
+	return n, nil
+}
+
+func (p *parser) waitNode(args []string) (node, error) {
+	if len(args) != 1 {
+		return nil, p.errorf("wait takes a number of frames")
+	}
+	nums, err := p.floats(args)
+	if err != nil {
+		return nil, err
+	}
+	return &waitNode{int(nums[0])}, nil
+}
+
+func (p *parser) setNode(args []string) (node, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, p.errorf("set takes a flag, and maybe true or false")
+	}
+	n := &setNode{args[0], true}
+	if len(args) == 2 {
+		v, err := strconv.ParseBool(args[1])
+		if err != nil {
+			return nil, p.errorf("set %s to %s: not true or false", args[0], args[1])
+		}
+		n.value = v
+	}
+	return n, nil
+}
+
+// placeNode parses spawn and warp, which both take a name and a position
+func (p *parser) placeNode(cmd string, args []string) (node, error) {
+	if len(args) != 4 {
+		return nil, p.errorf("%s takes a name and a position", cmd)
+	}
+	nums, err := p.floats(args[1:])
+	if err != nil {
+		return nil, err
+	}
+	return &placeNode{cmd == "warp", args[0], int(nums[0]), int(nums[1]), int(nums[2])}, nil
+}
+
+func (p *parser) floats(args []string) ([]float64, error) {
+	out := make([]float64, len(args))
+	for i, arg := range args {
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, p.errorf("%s is not a number", arg)
+		}
+		out[i] = f
+	}
+	return out, nil
+}
+
+// tokenize splits a line into arguments on spaces, keeping quoted arguments whole
+func tokenize(line string) ([]string, error) {
+	var out []string
+	var cur strings.Builder
+	quoted, inToken := false, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(line):
+			i++
+			cur.WriteByte(line[i])
+		case c == '"':
+			quoted = !quoted
+			inToken = true
+		case !quoted && (c == ' ' || c == '\t' || c == '\r'):
+			if inToken {
+				out = append(out, cur.String())
+				cur.Reset()
+				inToken = false
+			}
+		default:
+			cur.WriteByte(c)
+			inToken = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inToken {
+		out = append(out, cur.String())
+	}
+	return out, nil
+}
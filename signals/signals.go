@@ -0,0 +1,158 @@
+package signals
+
+import (
+	"fmt"
+
+	"enewey.com/golang-game/types"
+)
+
+// Signals are named on/off wires between the sources and sinks of a room. Sources (switches,
+// levers, timers) set signals, and sinks (doors, gates, spawners, platforms) listen for changes.
+// Logic nodes listen to some signals, and set another. A signal that has never been set is off.
+var (
+	values    = make(map[string]bool)
+	listeners = make(map[string][]func(bool))
+	timers    []*timer
+	pending   []change // changes waiting to be told to their listeners
+	settling  bool
+)
+
+// maxSettle caps how many changes a single Set can set off, so a node wired back into
+// its own inputs (e.g. a "not" feeding itself) can't loop forever
+const maxSettle = 1000
+
+type change struct {
+	name string
+	on   bool
+}
+
+// Set turns the named signal on or off, telling its listeners if it changed.
+// A signal set by a listener is changed once the listeners of the current change have all been told.
+func Set(name string, on bool) {
+	pending = append(pending, change{name, on})
+	if settling {
+		return
+	}
+	settling = true
+	defer func() { settling = false }()
+
+	for n := 0; len(pending) > 0; n++ {
+		if n >= maxSettle {
+			fmt.Printf("signal %s didn't settle; some node loops back on itself\n", name)
+			pending = nil
+			return
+		}
+		c := pending[0]
+		pending = pending[1:]
+		if values[c.name] == c.on {
+			continue
+		}
+		values[c.name] = c.on
+		for _, l := range listeners[c.name] {
+			l(c.on)
+		}
+	}
+}
+
+// On tells whether the named signal is on
+func On(name string) bool {
+	return values[name]
+}
+
+// Listen calls back whenever the named signal changes
+func Listen(name string, f func(on bool)) {
+	listeners[name] = append(listeners[name], f)
+}
+
+// Reset turns every signal off, and forgets every listener, node and timer
+func Reset() {
+	values = make(map[string]bool)
+	listeners = make(map[string][]func(bool))
+	timers = nil
+	pending = nil
+}
+
+// AddNode wires up a logic node, which sets the output signal from the input signals:
+//
+//	"and" - on while all inputs are on
+//	"or" - on while any input is on
+//	"not" - on while the first input is off
+//	"toggle" - flips every time an input turns on
+//	"latch" - turns on when an input turns on, and stays on
+func AddNode(kind string, inputs []string, output string) {
+	var eval func(changed string, on bool)
+	switch kind {
+	case "and":
+		eval = func(string, bool) {
+			all := len(inputs) > 0
+			for _, in := range inputs {
+				all = all && On(in)
+			}
+			Set(output, all)
+		}
+	case "or":
+		eval = func(string, bool) {
+			some := false
+			for _, in := range inputs {
+				some = some || On(in)
+			}
+			Set(output, some)
+		}
+	case "not":
+		eval = func(string, bool) {
+			Set(output, len(inputs) > 0 && !On(inputs[0]))
+		}
+	case "toggle":
+		eval = func(changed string, on bool) {
+			if changed != "" && on {
+				Set(output, !On(output))
+			}
+		}
+	case "latch":
+		eval = func(changed string, on bool) {
+			if changed == "" {
+				// settling; latch on if an input is already on
+				for _, in := range inputs {
+					on = on || On(in)
+				}
+			}
+			if on {
+				Set(output, true)
+			}
+		}
+	default:
+		fmt.Printf("unknown logic node %s\n", kind)
+		return
+	}
+	for _, in := range inputs {
+		in := in
+		Listen(in, func(on bool) { eval(in, on) })
+	}
+	// settle the output with the inputs as they are now
+	eval("", false)
+}
+
+// timer turns a signal on for some frames out of every period
+type timer struct {
+	signal     string
+	period, on types.Frame
+	elapsed    types.Frame
+}
+
+// AddTimer creates a timer that turns the signal on for the first frames of every period
+func AddTimer(signal string, period, on types.Frame) {
+	if period <= 0 {
+		fmt.Printf("timer %s needs a period\n", signal)
+		return
+	}
+	timers = append(timers, &timer{signal, period, on, 0})
+	Set(signal, on > 0)
+}
+
+// Tick runs the timers
+func Tick(df types.Frame) {
+	for _, t := range timers {
+		t.elapsed = (t.elapsed + df) % t.period
+		Set(t.signal, t.elapsed < t.on)
+	}
+}
@@ -0,0 +1,120 @@
+package sprites
+
+import (
+	"enewey.com/golang-game/clock"
+	"enewey.com/golang-game/types"
+)
+
+// ClipDirection describes the order in which the frames of a Clip are played.
+type ClipDirection int
+
+// Clip playback directions, mirroring the Aseprite frame tag directions.
+const (
+	Forward ClipDirection = iota
+	Reverse
+	PingPong
+)
+
+// Clip is a named animation: a sequence of sprites, each shown for a number of frames.
+type Clip struct {
+	name      string
+	frames    []*Sprite
+	durations []types.Frame
+	order     []int // indices into frames, in playback order
+	length    types.Frame
+}
+
+// NewClip creates a new animation clip. Each sprite in frames is shown for the
+// matching number of frames in durations.
+func NewClip(name string, frames []*Sprite, durations []types.Frame, dir ClipDirection) *Clip {
+	if len(frames) != len(durations) || len(frames) == 0 {
+		panic("tried to create clip with mismatched frames and durations")
+	}
+
+	var order []int
+	switch dir {
+	case Reverse:
+		for i := len(frames) - 1; i >= 0; i-- {
+			order = append(order, i)
+		}
+	case PingPong:
+		// plays 0..n-1 then back down, without repeating the end frames
+		for i := 0; i < len(frames); i++ {
+			order = append(order, i)
+		}
+		for i := len(frames) - 2; i > 0; i-- {
+			order = append(order, i)
+		}
+	default:
+		for i := 0; i < len(frames); i++ {
+			order = append(order, i)
+		}
+	}
+
+	var length types.Frame
+	for _, v := range order {
+		length += durations[v]
+	}
+
+	return &Clip{name, frames, durations, order, length}
+}
+
+// Name returns the name of the clip (i.e. the Aseprite tag name)
+func (c *Clip) Name() string { return c.name }
+
+// Len returns the number of distinct sprites in the clip
+func (c *Clip) Len() int { return len(c.frames) }
+
+// Duration returns how many frames it takes to play one full cycle of the clip
+func (c *Clip) Duration() types.Frame { return c.length }
+
+// Frame returns the sprite that should be shown after the clip has been playing
+// for the elapsed number of frames. Clips loop.
+func (c *Clip) Frame(elapsed types.Frame) *Sprite {
+	if c.length <= 0 {
+		return c.frames[c.order[0]]
+	}
+	t := elapsed % c.length
+	if t < 0 {
+		t += c.length
+	}
+	for _, v := range c.order {
+		if t < c.durations[v] {
+			return c.frames[v]
+		}
+		t -= c.durations[v]
+	}
+	return c.frames[c.order[len(c.order)-1]]
+}
+
+// ClipMap maps an int to an animation clip -- like the CharaMap, handy for use
+// with the Direction iota. Clips are played against the game clock.
+type ClipMap map[int]*Clip
+
+// NewCharaClipmap returns a new 4 directional animated spritemap for an actor
+func NewCharaClipmap(d, r, u, l *Clip) *ClipMap {
+	return &ClipMap{
+		int(types.Up):        u,
+		int(types.Down):      d,
+		int(types.Right):     r,
+		int(types.Left):      l,
+		int(types.UpRight):   u,
+		int(types.UpLeft):    u,
+		int(types.DownRight): d,
+		int(types.DownLeft):  d,
+	}
+}
+
+// NewStaticClipmap returns a spritemap that always plays the same clip.
+func NewStaticClipmap(c *Clip) *ClipMap {
+	return NewCharaClipmap(c, c, c, c)
+}
+
+// Sprite returns the current frame of the clip with the given ID
+func (cm *ClipMap) Sprite(id int) *Sprite {
+	clip := (*cm)[id]
+	if clip == nil {
+		return nil
+	}
+	return clip.Frame(types.Frame(clock.Get().Int64()))
+}
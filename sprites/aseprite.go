@@ -0,0 +1,195 @@
+package sprites
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+
+	"enewey.com/golang-game/types"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// msPerFrame is used to convert Aseprite frame durations (milliseconds) into game frames.
+const msPerFrame = 1000.0 / 60.0
+
+// AsepriteData is unmarshaled from the JSON exported by Aseprite alongside a sprite sheet.
+// Both the "Array" and "Hash" frame export formats are supported.
+type AsepriteData struct {
+	Frames []*AsepriteFrame `json:"-"`
+	Meta   AsepriteMeta     `json:"meta"`
+}
+
+// AsepriteFrame is a single frame of an Aseprite export
+type AsepriteFrame struct {
+	Filename         string       `json:"filename"`
+	Frame            asepriteRect `json:"frame"`
+	Rotated          bool         `json:"rotated"`
+	Trimmed          bool         `json:"trimmed"`
+	SpriteSourceSize asepriteRect `json:"spriteSourceSize"`
+	SourceSize       asepriteRect `json:"sourceSize"`
+	Duration         int          `json:"duration"` // milliseconds
+}
+
+// AsepriteMeta is the metadata of an Aseprite export
+type AsepriteMeta struct {
+	Image     string         `json:"image"`
+	FrameTags []*AsepriteTag `json:"frameTags"`
+}
+
+// AsepriteTag is a named range of frames, i.e. an animation
+type AsepriteTag struct {
+	Name      string `json:"name"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction"`
+}
+
+type asepriteRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// ParseAseprite unmarshals the bytes of an Aseprite JSON export.
+func ParseAseprite(body []byte) (*AsepriteData, error) {
+	var raw struct {
+		Frames json.RawMessage `json:"frames"`
+		Meta   AsepriteMeta    `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	out := &AsepriteData{Meta: raw.Meta}
+	frames := bytes.TrimSpace(raw.Frames)
+	if len(frames) == 0 {
+		return out, nil
+	}
+
+	// "Array" export
+	if frames[0] == '[' {
+		if err := json.Unmarshal(frames, &out.Frames); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	// "Hash" export -- frames are keyed by filename, and the key order is the frame order,
+	// so walk the tokens rather than unmarshaling into a map.
+	dec := json.NewDecoder(bytes.NewReader(frames))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		frame := &AsepriteFrame{}
+		if err := dec.Decode(frame); err != nil {
+			return nil, err
+		}
+		frame.Filename = fmt.Sprintf("%v", tok)
+		out.Frames = append(out.Frames, frame)
+	}
+	return out, nil
+}
+
+// AsepriteSheet is a sprite sheet sliced according to an Aseprite export, rather than a fixed grid.
+type AsepriteSheet struct {
+	sprites []*Sprite
+	clips   map[string]*Clip
+}
+
+// NewAsepriteSheet slices the image into sprites using the frame rects of the
+// Aseprite data, and creates an animation clip for each frame tag.
+func NewAsepriteSheet(img *ebiten.Image, data *AsepriteData) *AsepriteSheet {
+	sheet := &AsepriteSheet{
+		make([]*Sprite, len(data.Frames)),
+		make(map[string]*Clip),
+	}
+	durations := make([]types.Frame, len(data.Frames))
+
+	for i, f := range data.Frames {
+		sheet.sprites[i] = asepriteSprite(img, f)
+		durations[i] = msToFrames(f.Duration)
+	}
+
+	for _, tag := range data.Meta.FrameTags {
+		if tag.From < 0 || tag.To >= len(data.Frames) || tag.From > tag.To {
+			fmt.Printf("skipping aseprite tag %s with bad frame range %d-%d\n", tag.Name, tag.From, tag.To)
+			continue
+		}
+		sheet.clips[tag.Name] = NewClip(
+			tag.Name,
+			sheet.sprites[tag.From:tag.To+1],
+			durations[tag.From:tag.To+1],
+			asepriteDirection(tag.Direction),
+		)
+	}
+
+	return sheet
+}
+
+// GetSprite returns the sprite for the numbered frame of the export
+func (s *AsepriteSheet) GetSprite(num int) *Sprite {
+	if num < 0 || num >= len(s.sprites) {
+		return nil
+	}
+	return s.sprites[num]
+}
+
+// Len returns the number of frames in the sheet
+func (s *AsepriteSheet) Len() int { return len(s.sprites) }
+
+// Clip returns the animation clip for the named frame tag, or nil if there is none.
+func (s *AsepriteSheet) Clip(name string) *Clip { return s.clips[name] }
+
+// Clips returns all of the named animation clips
+func (s *AsepriteSheet) Clips() map[string]*Clip { return s.clips }
+
+func asepriteSprite(img *ebiten.Image, f *AsepriteFrame) *Sprite {
+	w, h := f.Frame.W, f.Frame.H
+	if f.Rotated {
+		// rotated frames are stored sideways in the sheet; this isn't supported,
+		// so just grab the region as-is.
+		fmt.Printf("aseprite frame %s is rotated; export without rotation\n", f.Filename)
+		w, h = h, w
+	}
+	sub := img.SubImage(image.Rect(f.Frame.X, f.Frame.Y, f.Frame.X+w, f.Frame.Y+h)).(*ebiten.Image)
+	if !f.Trimmed {
+		return &Sprite{sub}
+	}
+
+	// trimmed frames get re-padded to their source size, so that all frames in
+	// an animation share the same origin.
+	full, err := ebiten.NewImage(f.SourceSize.W, f.SourceSize.H, ebiten.FilterDefault)
+	if err != nil {
+		panic(err)
+	}
+	opt := &ebiten.DrawImageOptions{}
+	opt.GeoM.Translate(float64(f.SpriteSourceSize.X), float64(f.SpriteSourceSize.Y))
+	full.DrawImage(sub, opt)
+	return &Sprite{full}
+}
+
+func asepriteDirection(dir string) ClipDirection {
+	switch dir {
+	case "reverse":
+		return Reverse
+	case "pingpong":
+		return PingPong
+	default:
+		return Forward
+	}
+}
+
+func msToFrames(ms int) types.Frame {
+	f := types.Frame(float64(ms)/msPerFrame + 0.5)
+	if f < 1 {
+		return 1
+	}
+	return f
+}
@@ -29,6 +29,20 @@ func (s *Sprite) Draw(x, y int, img *ebiten.Image) *ebiten.Image {
 	return img
 }
 
+// DrawFaded - draw this sprite centered on x/y on the given image, scaled about its center and faded to an alpha
+func (s *Sprite) DrawFaded(x, y int, scale, alpha float64, img *ebiten.Image) *ebiten.Image {
+	w, h := s.img.Size()
+	opt := &ebiten.DrawImageOptions{}
+	opt.GeoM.Translate(-float64(w)/2, -float64(h)/2)
+	opt.GeoM.Scale(scale, scale)
+	opt.GeoM.Translate(float64(x), float64(y))
+	opt.ColorM.Scale(1, 1, 1, alpha)
+	opt.Filter = ebiten.FilterNearest
+
+	img.DrawImage(s.img, opt)
+	return img
+}
+
 // NewCompoundSprite - create a single sprite composed of tiles, specifying an array
 // of tiles to draw, the number of rows and columns, and the x/y dimensions of each tile in pixels.
 func NewCompoundSprite(sprites []*Sprite, rows, cols, tilex, tiley int) *Sprite {
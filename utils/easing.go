@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"math"
+)
+
+// Easing maps linear progress t (0 to 1) to eased progress.
+// Eased progress starts at 0 and ends at 1, but may overshoot in between.
+type Easing func(t float64) float64
+
+// Linear - no easing
+func Linear(t float64) float64 { return t }
+
+// EaseInQuad - accelerates from zero velocity
+func EaseInQuad(t float64) float64 { return t * t }
+
+// EaseOutQuad - decelerates to zero velocity
+func EaseOutQuad(t float64) float64 { return t * (2 - t) }
+
+// EaseInOutQuad - accelerates until halfway, then decelerates
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// EaseInCubic - accelerates from zero velocity
+func EaseInCubic(t float64) float64 { return t * t * t }
+
+// EaseOutCubic - decelerates to zero velocity
+func EaseOutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// EaseInOutCubic - accelerates until halfway, then decelerates
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return 0.5*t*t*t + 1
+}
+
+// EaseInSine - accelerates from zero velocity along a sine curve
+func EaseInSine(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) }
+
+// EaseOutSine - decelerates to zero velocity along a sine curve
+func EaseOutSine(t float64) float64 { return math.Sin(t * math.Pi / 2) }
+
+// EaseInOutSine - accelerates then decelerates along a sine curve
+func EaseInOutSine(t float64) float64 { return -(math.Cos(math.Pi*t) - 1) / 2 }
+
+// EaseOutBounce - bounces to a stop at the end
+func EaseOutBounce(t float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	}
+	t -= 2.625 / d
+	return n*t*t + 0.984375
+}
+
+// EaseInBounce - bounces away from the start
+func EaseInBounce(t float64) float64 { return 1 - EaseOutBounce(1-t) }
+
+// EaseOutElastic - overshoots and springs back into place at the end
+func EaseOutElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*(2*math.Pi/3)) + 1
+}
+
+// EaseInElastic - winds up like a spring before leaving the start
+func EaseInElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	return -math.Pow(2, 10*t-10) * math.Sin((t*10-10.75)*(2*math.Pi/3))
+}
+
+var easings = map[string]Easing{
+	"linear":     Linear,
+	"quad-in":    EaseInQuad,
+	"quad-out":   EaseOutQuad,
+	"quad":       EaseInOutQuad,
+	"cubic-in":   EaseInCubic,
+	"cubic-out":  EaseOutCubic,
+	"cubic":      EaseInOutCubic,
+	"sine-in":    EaseInSine,
+	"sine-out":   EaseOutSine,
+	"sine":       EaseInOutSine,
+	"bounce-in":  EaseInBounce,
+	"bounce":     EaseOutBounce,
+	"elastic-in": EaseInElastic,
+	"elastic":    EaseOutElastic,
+}
+
+// EasingByName looks up an easing function by name, e.g. "quad-in", "bounce", "sine".
+// Names without a suffix ease both in and out, except bounce and elastic which ease out.
+// Unknown (or empty) names are linear.
+func EasingByName(name string) Easing {
+	if e, ok := easings[name]; ok {
+		return e
+	}
+	return Linear
+}
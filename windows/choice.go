@@ -0,0 +1,84 @@
+package windows
+
+import (
+	"image/color"
+	"strings"
+
+	"enewey.com/golang-game/config"
+	"enewey.com/golang-game/events"
+	"enewey.com/golang-game/input"
+	"enewey.com/golang-game/types"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+// height of a row of the choice window
+const choiceRowH = 16
+
+// ChoiceWindow shows a prompt and a list of options. The cursor moves with up and down, and
+// confirm picks the option under the cursor, putting its reply onto the event bus. An option
+// must be picked; cancel does nothing.
+type ChoiceWindow struct {
+	*BaseWindow
+	prompt  string
+	options []string
+	replies []*events.Event
+	cursor  int
+	elapsed types.Frame
+}
+
+// NewChoiceWindow returns a new choice window, where each option has the event to reply with when
+// it is picked (or nil, to just close the window)
+func NewChoiceWindow(x, y, w, h int, c color.Color, prompt string, options []string, replies []*events.Event) *ChoiceWindow {
+	return &ChoiceWindow{NewBlankWindow(x, y, w, h, c), prompt, options, replies, 0, 0}
+}
+
+// Act w
+func (w *ChoiceWindow) Act(df types.Frame) {
+	w.elapsed += df
+}
+
+// Draw draws the prompt, with the options underneath it
+func (w *ChoiceWindow) Draw(img *ebiten.Image, ox, oy int) {
+	w.skin.Sprite.Draw(w.x, w.y, img)
+
+	y := w.y
+	if w.prompt != "" {
+		ebitenutil.DebugPrintAt(img, w.prompt, w.x, y)
+		y += choiceRowH * (strings.Count(w.prompt, "\n") + 1)
+	}
+	for i, opt := range w.options {
+		if i == w.cursor {
+			ebitenutil.DebugPrintAt(img, ">", w.x+2, y)
+		}
+		ebitenutil.DebugPrintAt(img, opt, w.x+12, y)
+		y += choiceRowH
+	}
+}
+
+// HandleInput - the choice window consumes input until an option is picked
+func (w *ChoiceWindow) HandleInput(state input.Input) bool {
+	if len(w.options) == 0 {
+		w.dispose()
+		return true
+	}
+	if w.elapsed < 5 {
+		// don't let the press that opened the window pick an option
+		return true
+	}
+	cfg := config.Get()
+
+	if state[cfg.KeyUp()].JustPressed() {
+		w.cursor = (w.cursor + len(w.options) - 1) % len(w.options)
+	}
+	if state[cfg.KeyDown()].JustPressed() {
+		w.cursor = (w.cursor + 1) % len(w.options)
+	}
+	if state[cfg.KeyConfirm()].JustPressed() {
+		if w.cursor < len(w.replies) && w.replies[w.cursor] != nil {
+			events.Enqueue(w.replies[w.cursor])
+		}
+		w.dispose()
+	}
+	return true
+}
@@ -10,6 +10,8 @@ import (
 // Window types as Event codes
 const (
 	Message = iota
+	Inventory
+	Choice
 )
 
 // InterpretEvent translates an event into a window
@@ -20,6 +22,10 @@ func InterpretEvent(ev *events.Event) Window {
 	switch ev.Code() {
 	case Message:
 		return messageWindowEvent(p)
+	case Inventory:
+		return inventoryWindowEvent(p)
+	case Choice:
+		return choiceWindowEvent(p)
 	default:
 		fmt.Printf("unknown window event code %d\n", ev.Code())
 		return NewMessageWindow(0, 0, 100, 100, cfg.WindowColor(), "", cfg.TextSpeed())
@@ -31,5 +37,24 @@ func messageWindowEvent(p []interface{}) *MessageWindow {
 	x, y, w, h := p[0].(int), p[1].(int), p[2].(int), p[3].(int)
 	msg := p[4].(string)
 	fmt.Printf("message window interpreted %d %d %d %d %s", x, y, w, h, msg)
-	return NewMessageWindow(x, y, w, h, cfg.WindowColor(), msg, cfg.TextSpeed())
+	win := NewMessageWindow(x, y, w, h, cfg.WindowColor(), msg, cfg.TextSpeed())
+	if len(p) > 5 && p[5] != nil {
+		win.Then(p[5].(*events.Event))
+	}
+	return win
+}
+
+func inventoryWindowEvent(p []interface{}) *InventoryWindow {
+	cfg := config.Get()
+	x, y, w, h := p[0].(int), p[1].(int), p[2].(int), p[3].(int)
+	fmt.Printf("inventory window interpreted %d %d %d %d\n", x, y, w, h)
+	return NewInventoryWindow(x, y, w, h, cfg.WindowColor())
+}
+
+func choiceWindowEvent(p []interface{}) *ChoiceWindow {
+	cfg := config.Get()
+	x, y, w, h := p[0].(int), p[1].(int), p[2].(int), p[3].(int)
+	prompt, options, replies := p[4].(string), p[5].([]string), p[6].([]*events.Event)
+	fmt.Printf("choice window interpreted %d %d %d %d %v\n", x, y, w, h, options)
+	return NewChoiceWindow(x, y, w, h, cfg.WindowColor(), prompt, options, replies)
 }
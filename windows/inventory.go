@@ -0,0 +1,105 @@
+package windows
+
+import (
+	"fmt"
+	"image/color"
+
+	"enewey.com/golang-game/config"
+	"enewey.com/golang-game/input"
+	"enewey.com/golang-game/items"
+	"enewey.com/golang-game/types"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+// height of a row of the inventory window
+const inventoryRowH = 18
+
+// InventoryWindow lists the items in the player's inventory. The cursor moves with up and down,
+// confirm uses the item under the cursor (closing the window), and cancel closes the window.
+type InventoryWindow struct {
+	*BaseWindow
+	cursor int
+	top    int // first row shown, when there are more items than fit
+}
+
+// NewInventoryWindow returns a new inventory window
+func NewInventoryWindow(x, y, w, h int, c color.Color) *InventoryWindow {
+	return &InventoryWindow{NewBlankWindow(x, y, w, h, c), 0, 0}
+}
+
+func (w *InventoryWindow) rows() int {
+	return (w.h - 4) / inventoryRowH
+}
+
+// clamp keeps the cursor on one of the n items held, which may have changed since the last frame
+func (w *InventoryWindow) clamp(n int) {
+	if w.cursor >= n {
+		w.cursor = n - 1
+	}
+	if w.cursor < 0 {
+		w.cursor = 0
+	}
+}
+
+// Act keeps the cursor on an item, and the item in view
+func (w *InventoryWindow) Act(df types.Frame) {
+	w.clamp(len(items.Held()))
+	if w.cursor < w.top {
+		w.top = w.cursor
+	}
+	if w.cursor >= w.top+w.rows() {
+		w.top = w.cursor - w.rows() + 1
+	}
+}
+
+// Draw draws a row for each item in view, with its icon, name and count
+func (w *InventoryWindow) Draw(img *ebiten.Image, ox, oy int) {
+	w.skin.Sprite.Draw(w.x, w.y, img)
+
+	held := items.Held()
+	if len(held) == 0 {
+		ebitenutil.DebugPrintAt(img, "No items", w.x+4, w.y+2)
+		return
+	}
+	for i := w.top; i < len(held) && i < w.top+w.rows(); i++ {
+		s := held[i]
+		y := w.y + 2 + (i-w.top)*inventoryRowH
+		if i == w.cursor {
+			ebitenutil.DebugPrintAt(img, ">", w.x+2, y)
+		}
+		if s.Item.Icon != nil {
+			s.Item.Icon.Draw(w.x+10, y, img)
+		}
+		label := s.Item.Name
+		if s.Item.Stackable {
+			label = fmt.Sprintf("%s x%d", label, s.Count)
+		}
+		ebitenutil.DebugPrintAt(img, label, w.x+30, y)
+	}
+}
+
+// HandleInput - the inventory window consumes input for as long as it is open
+func (w *InventoryWindow) HandleInput(state input.Input) bool {
+	cfg := config.Get()
+	held := items.Held()
+	w.clamp(len(held))
+
+	if state[cfg.KeyUp()].JustPressed() && len(held) > 0 {
+		w.cursor = (w.cursor + len(held) - 1) % len(held)
+	}
+	if state[cfg.KeyDown()].JustPressed() && len(held) > 0 {
+		w.cursor = (w.cursor + 1) % len(held)
+	}
+	if state[cfg.KeyConfirm()].JustPressed() {
+		if len(held) == 0 {
+			w.dispose()
+		} else if items.Use(held[w.cursor]) {
+			w.dispose()
+		}
+	}
+	if state[cfg.KeyCancel()].JustPressed() || state[cfg.KeyMenu()].JustPressed() {
+		w.dispose()
+	}
+	return true
+}
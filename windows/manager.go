@@ -23,6 +23,14 @@ func (m *Manager) AddWindow(win Window) {
 	m.windows = append(m.windows, win)
 }
 
+// Clear disposes of every window in the window stack
+func (m *Manager) Clear() {
+	m.windows = []Window{}
+}
+
+// HasFocus tells whether any windows are open, i.e. whether windows have focus over actors.
+func (m *Manager) HasFocus() bool { return len(m.windows) > 0 }
+
 // HandleInput - handles the input state. Returns true if input is consumed.
 func (m *Manager) HandleInput(state input.Input, df int) bool {
 	if len(m.windows) == 0 {
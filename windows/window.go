@@ -4,6 +4,7 @@ import (
 	"image/color"
 
 	"enewey.com/golang-game/config"
+	"enewey.com/golang-game/events"
 	"enewey.com/golang-game/input"
 	"enewey.com/golang-game/sprites"
 	"enewey.com/golang-game/types"
@@ -48,13 +49,26 @@ type MessageWindow struct {
 	speed       types.Frame
 	elapsed     types.Frame
 	end         types.Frame
+	then        *events.Event // put onto the event bus once the window is dismissed
 }
 
 // NewMessageWindow returns a new message window, where the speed is how many frames
 // it takes between each letter
 func NewMessageWindow(x, y, w, h int, c color.Color, message string, speed types.Frame) *MessageWindow {
 	end := len(message) * speed
-	return &MessageWindow{NewBlankWindow(x, y, w, h, c), message, "", speed, 0, end}
+	return &MessageWindow{NewBlankWindow(x, y, w, h, c), message, "", speed, 0, end, nil}
+}
+
+// Then sets an event to put onto the event bus once the message is dismissed, e.g. to carry on a dialogue
+func (w *MessageWindow) Then(ev *events.Event) {
+	w.then = ev
+}
+
+func (w *MessageWindow) dismiss() {
+	w.dispose()
+	if w.then != nil {
+		events.Enqueue(w.then)
+	}
 }
 
 // Act ticks up the window elapsed frames and shows more of the message
@@ -87,7 +101,7 @@ func (w *MessageWindow) HandleInput(state input.Input) bool {
 
 	if state[cfg.KeyConfirm()].JustPressed() {
 		if w.elapsed >= w.end {
-			w.dispose()
+			w.dismiss()
 		} else {
 			w.elapsed = w.end
 		}
@@ -95,7 +109,7 @@ func (w *MessageWindow) HandleInput(state input.Input) bool {
 
 	if state[cfg.KeyCancel()].JustPressed() {
 		w.elapsed = w.end
-		w.dispose()
+		w.dismiss()
 	}
 	return true
 }